@@ -0,0 +1,63 @@
+package bin
+
+import (
+	"errors"
+	"math/big"
+)
+
+// base58 with bitcoin alphabet
+const base58Alphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+
+var (
+	errInvalidBase58 = errors.New("bin.decodeBase58-Error: invalid base58 string")
+
+	base58Index = func() (idx [256]int8) {
+		for i := range idx {
+			idx[i] = -1
+		}
+		for i, c := range base58Alphabet {
+			idx[c] = int8(i)
+		}
+		return
+	}()
+)
+
+func encodeBase58(b []byte) string {
+	zeros := 0
+	for zeros < len(b) && b[zeros] == 0 {
+		zeros++
+	}
+	x := new(big.Int).SetBytes(b[zeros:])
+	radix := big.NewInt(58)
+	mod := new(big.Int)
+	buf := make([]byte, 0, len(b)*138/100+1)
+	for x.Sign() > 0 {
+		x.DivMod(x, radix, mod)
+		buf = append(buf, base58Alphabet[mod.Int64()])
+	}
+	for i := 0; i < zeros; i++ {
+		buf = append(buf, base58Alphabet[0])
+	}
+	for i, j := 0, len(buf)-1; i < j; i, j = i+1, j-1 {
+		buf[i], buf[j] = buf[j], buf[i]
+	}
+	return string(buf)
+}
+
+func decodeBase58(s string) ([]byte, error) {
+	zeros := 0
+	for zeros < len(s) && s[zeros] == base58Alphabet[0] {
+		zeros++
+	}
+	x := new(big.Int)
+	radix := big.NewInt(58)
+	for i := zeros; i < len(s); i++ {
+		d := base58Index[s[i]]
+		if d < 0 {
+			return nil, errInvalidBase58
+		}
+		x.Mul(x, radix)
+		x.Add(x, big.NewInt(int64(d)))
+	}
+	return append(make([]byte, zeros), x.Bytes()...), nil
+}
@@ -0,0 +1,163 @@
+package bin
+
+import (
+	"errors"
+	"math"
+)
+
+// BitWriter writes bit fields (most significant bit first) to Writer.
+// Align must be called after the last field and before any byte-level writes to underlying Writer.
+type BitWriter struct {
+	w   *Writer
+	cur byte // pending bits
+	n   int  // number of pending bits
+}
+
+// BitReader reads bit fields written by BitWriter.
+type BitReader struct {
+	r   *Reader
+	cur byte // current byte
+	n   int  // number of unread bits of current byte
+}
+
+var (
+	errInvalidBitsCount = errors.New("bin.Bits-Error: invalid number of bits")
+	errInvalidExpGolomb = errors.New("bin.Bits-Error: invalid Exp-Golomb code")
+)
+
+func NewBitWriter(w *Writer) *BitWriter {
+	return &BitWriter{w: w}
+}
+
+func NewBitReader(r *Reader) *BitReader {
+	return &BitReader{r: r}
+}
+
+func (b *BitWriter) Error() error {
+	return b.w.err
+}
+
+// WriteBits writes n lower bits of v (0 <= n <= 64)
+func (b *BitWriter) WriteBits(v uint64, n int) error {
+	if n < 0 || n > 64 {
+		b.w.SetError(errInvalidBitsCount)
+	}
+	for n > 0 && b.w.err == nil {
+		k := min(8-b.n, n)
+		n -= k
+		b.cur |= byte(v>>n&(1<<k-1)) << (8 - b.n - k)
+		if b.n += k; b.n == 8 {
+			b.w.WriteByte(b.cur)
+			b.cur, b.n = 0, 0
+		}
+	}
+	return b.w.err
+}
+
+func (b *BitWriter) WriteBool(f bool) error {
+	if f {
+		return b.WriteBits(1, 1)
+	}
+	return b.WriteBits(0, 1)
+}
+
+// WriteUE writes unsigned Exp-Golomb code of v
+func (b *BitWriter) WriteUE(v uint64) error {
+	if v == math.MaxUint64 { // v+1 = 1<<64
+		b.WriteBits(0, 64)
+		b.WriteBits(1, 1)
+		return b.WriteBits(0, 64)
+	}
+	k := 63
+	for v+1 < 1<<k {
+		k--
+	}
+	b.WriteBits(0, k)
+	return b.WriteBits(v+1, k+1)
+}
+
+// WriteSE writes signed Exp-Golomb code of v (0, 1, -1, 2, -2, ... are mapped to 0, 1, 2, 3, 4, ...)
+func (b *BitWriter) WriteSE(v int64) error {
+	if v > 0 || v == math.MinInt64 {
+		return b.WriteUE(uint64(v)<<1 - 1)
+	}
+	return b.WriteUE(uint64(-v) << 1)
+}
+
+// Align writes pending bits padded with zeros to byte boundary
+func (b *BitWriter) Align() error {
+	if b.n > 0 {
+		b.WriteBits(0, 8-b.n)
+	}
+	return b.w.err
+}
+
+func (b *BitReader) Error() error {
+	return b.r.err
+}
+
+// ReadBits reads n bits (0 <= n <= 64)
+func (b *BitReader) ReadBits(n int) (v uint64, err error) {
+	if n < 0 || n > 64 {
+		b.r.SetError(errInvalidBitsCount)
+		return 0, b.r.err
+	}
+	for n > 0 {
+		if b.n == 0 {
+			if b.cur, err = b.r.ReadByte(); err != nil {
+				return 0, err
+			}
+			b.n = 8
+		}
+		k := min(b.n, n)
+		v = v<<k | uint64(b.cur>>(b.n-k))&(1<<k-1)
+		b.n -= k
+		n -= k
+	}
+	return v, b.r.err
+}
+
+func (b *BitReader) ReadBool() (bool, error) {
+	v, err := b.ReadBits(1)
+	return v == 1, err
+}
+
+// ReadUE reads unsigned Exp-Golomb code
+func (b *BitReader) ReadUE() (uint64, error) {
+	k := 0
+	for {
+		bit, err := b.ReadBits(1)
+		if err != nil {
+			return 0, err
+		}
+		if bit == 1 {
+			break
+		}
+		if k++; k > 64 {
+			b.r.SetError(errInvalidExpGolomb)
+			return 0, b.r.err
+		}
+	}
+	v, err := b.ReadBits(k)
+	if k == 64 { // v+1 = 1<<64
+		if err == nil && v != 0 {
+			b.r.SetError(errInvalidExpGolomb)
+		}
+		return math.MaxUint64, b.r.err
+	}
+	return 1<<k | v - 1, err
+}
+
+// ReadSE reads signed Exp-Golomb code
+func (b *BitReader) ReadSE() (int64, error) {
+	u, err := b.ReadUE()
+	if u&1 == 1 {
+		return int64(u>>1 + 1), err
+	}
+	return -int64(u >> 1), err
+}
+
+// Align skips unread bits of current byte
+func (b *BitReader) Align() {
+	b.cur, b.n = 0, 0
+}
@@ -0,0 +1,98 @@
+package bin
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBitWriter(t *testing.T) {
+	buf := NewBuffer(nil)
+	w := NewBitWriter(&buf.Writer)
+
+	w.WriteBits(0b101, 3)
+	w.WriteBool(true)
+	w.WriteBits(0xabc, 12)
+	w.WriteBool(false)
+	err := w.Align()
+
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{0b1011_1010, 0b1011_1100, 0}, buf.Bytes())
+}
+
+func TestBitReader(t *testing.T) {
+	buf := NewBuffer(nil)
+	w := NewBitWriter(&buf.Writer)
+	w.WriteBits(5, 3)
+	w.WriteBits(math.MaxUint64, 64)
+	w.WriteBits(0x1234567890, 40)
+	w.WriteBool(true)
+	w.Align()
+	buf.WriteString("abc")
+
+	r := NewBitReader(&buf.Reader)
+	v1, _ := r.ReadBits(3)
+	v2, _ := r.ReadBits(64)
+	v3, _ := r.ReadBits(40)
+	f, _ := r.ReadBool()
+	r.Align()
+	s, err := buf.ReadString()
+
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(5), v1)
+	assert.Equal(t, uint64(math.MaxUint64), v2)
+	assert.Equal(t, uint64(0x1234567890), v3)
+	assert.True(t, f)
+	assert.Equal(t, "abc", s)
+}
+
+func TestBitWriter_ExpGolomb(t *testing.T) {
+	uu := []uint64{0, 1, 2, 3, 7, 100, 1 << 40, math.MaxUint64 - 1, math.MaxUint64}
+	ss := []int64{0, 1, -1, 2, -2, 1000, -1000, math.MaxInt64, math.MinInt64 + 1, math.MinInt64}
+
+	buf := NewBuffer(nil)
+	w := NewBitWriter(&buf.Writer)
+	for _, u := range uu {
+		w.WriteUE(u)
+	}
+	for _, s := range ss {
+		w.WriteSE(s)
+	}
+	w.Align()
+
+	r := NewBitReader(&buf.Reader)
+	for _, u := range uu {
+		v, err := r.ReadUE()
+		assert.NoError(t, err)
+		assert.Equal(t, u, v)
+	}
+	for _, s := range ss {
+		v, err := r.ReadSE()
+		assert.NoError(t, err)
+		assert.Equal(t, s, v)
+	}
+}
+
+func TestBitWriter_ExpGolombCodes(t *testing.T) {
+	buf := NewBuffer(nil)
+	w := NewBitWriter(&buf.Writer)
+
+	w.WriteUE(0)  // 1
+	w.WriteUE(1)  // 010
+	w.WriteUE(4)  // 00101
+	w.WriteSE(-1) // 011
+	w.Align()
+
+	assert.Equal(t, []byte{0b1010_0010, 0b1011_0000}, buf.Bytes())
+}
+
+func TestBitReader_errors(t *testing.T) {
+	r := NewBitReader(&NewBuffer(make([]byte, 9)).Reader)
+
+	_, err1 := r.ReadUE()
+	_, err2 := NewBitReader(&NewBuffer(nil).Reader).ReadBits(1)
+
+	assert.Error(t, err1)
+	assert.Error(t, err2)
+}
@@ -1,25 +1,204 @@
 package bin
 
 import (
+	"database/sql/driver"
+	"encoding/base64"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
 )
 
 type Bytes []byte
 
+// TextEncoding is text form of binary data
+type TextEncoding int
+
+const (
+	EncodingHex       TextEncoding = iota // "0102ff"
+	Encoding0xHex                         // "0x0102ff"
+	EncodingBase64                        // standard base64 with padding
+	EncodingBase64URL                     // url-safe base64 without padding
+	EncodingBase58                        // base58 with bitcoin alphabet
+)
+
+var errInvalidTextEncoding = errors.New("bin.Bytes-Error: invalid text encoding")
+
 func (b Bytes) String() string {
 	return hex.EncodeToString(b)
 }
 
-func (b Bytes) MarshalJSON() ([]byte, error) {
-	return []byte(`"` + b.String() + `"`), nil
+// EncodeToString returns text form of b in given encoding.
+func (b Bytes) EncodeToString(enc TextEncoding) string {
+	switch enc {
+	case Encoding0xHex:
+		return "0x" + hex.EncodeToString(b)
+	case EncodingBase64:
+		return base64.StdEncoding.EncodeToString(b)
+	case EncodingBase64URL:
+		return base64.RawURLEncoding.EncodeToString(b)
+	case EncodingBase58:
+		return encodeBase58(b)
+	default:
+		return hex.EncodeToString(b)
+	}
+}
+
+// DecodeBytes decodes string in given text encoding.
+func DecodeBytes(s string, enc TextEncoding) (Bytes, error) {
+	switch enc {
+	case EncodingHex:
+		return hex.DecodeString(s)
+	case Encoding0xHex:
+		if !strings.HasPrefix(s, "0x") && !strings.HasPrefix(s, "0X") {
+			return nil, hex.InvalidByteError('x')
+		}
+		return hex.DecodeString(s[2:])
+	case EncodingBase64:
+		return base64.StdEncoding.Strict().DecodeString(s)
+	case EncodingBase64URL:
+		return base64.RawURLEncoding.Strict().DecodeString(strings.TrimRight(s, "="))
+	case EncodingBase58:
+		return decodeBase58(s)
+	}
+	return nil, errInvalidTextEncoding
+}
+
+// decodeHex decodes hex with optional 0x prefix
+func decodeHex(s string) (Bytes, error) {
+	if strings.HasPrefix(s, "0x") || strings.HasPrefix(s, "0X") {
+		s = s[2:]
+	}
+	return hex.DecodeString(s)
 }
 
-func (b *Bytes) UnmarshalJSON(data []byte) (err error) {
+// textEncodings is order in which parseBytes detects encoding of text
+var textEncodings = []TextEncoding{Encoding0xHex, EncodingHex, EncodingBase64, EncodingBase58, EncodingBase64URL}
+
+// parseBytes decodes text in any of text encodings. Own encoding of type (enc) is tried first,
+// then 0x-prefixed hex, hex, standard base64 (padded), base58 and url-safe base64 (unpadded).
+// Text valid in several encodings is decoded by the first one, e.g. "deadbeef" is hex for Bytes.
+func parseBytes(s string, enc TextEncoding) (Bytes, error) {
+	b, err := DecodeBytes(s, enc)
+	for _, e := range textEncodings {
+		if err == nil {
+			break
+		}
+		if e != enc {
+			b, err = DecodeBytes(s, e)
+		}
+	}
+	if err != nil {
+		return nil, errInvalidTextEncoding
+	}
+	return b, nil
+}
+
+func marshalBytesJSON(b []byte, enc TextEncoding) ([]byte, error) {
+	return []byte(`"` + Bytes(b).EncodeToString(enc) + `"`), nil
+}
+
+func unmarshalBytesJSON(b *[]byte, data []byte, enc TextEncoding) (err error) {
+	if string(data) == "null" {
+		*b = nil
+		return nil
+	}
 	var s string
 	if err = json.Unmarshal(data, &s); err != nil {
 		return err
 	}
-	*b, err = hex.DecodeString(s)
+	*b, err = parseBytes(s, enc)
+	return
+}
+
+func scanBytes(b *[]byte, src any) error {
+	switch v := src.(type) {
+	case nil:
+		*b = nil
+	case []byte:
+		*b = append([]byte{}, v...)
+	case string:
+		*b = []byte(v)
+	default:
+		return fmt.Errorf("bin.Bytes.Scan-Error: unsupported type %T", src)
+	}
+	return nil
+}
+
+func bytesValue(b []byte) (driver.Value, error) {
+	if b == nil {
+		return nil, nil
+	}
+	return b, nil
+}
+
+func readBytesTo(r *Reader, b *[]byte) {
+	*b, _ = r.ReadBytes()
+}
+
+func unmarshalBytesText(b *[]byte, text []byte, enc TextEncoding) (err error) {
+	*b, err = parseBytes(string(text), enc)
 	return
 }
+
+//----------- Bytes --------------
+
+// Bytes, like EncodedBytes, implements encoding.TextMarshaler, json.Marshaler, sql.Scanner and driver.Valuer.
+// Text and JSON are decoded by parseBytes (in any text encoding).
+
+func (b Bytes) TextEncoding() TextEncoding   { return EncodingHex }
+func (b Bytes) MarshalText() ([]byte, error) { return []byte(b.String()), nil }
+func (b *Bytes) UnmarshalText(text []byte) error {
+	return unmarshalBytesText((*[]byte)(b), text, EncodingHex)
+}
+func (b Bytes) MarshalJSON() ([]byte, error) { return marshalBytesJSON(b, EncodingHex) }
+func (b *Bytes) UnmarshalJSON(data []byte) error {
+	return unmarshalBytesJSON((*[]byte)(b), data, EncodingHex)
+}
+func (b *Bytes) Scan(src any) error          { return scanBytes((*[]byte)(b), src) }
+func (b Bytes) Value() (driver.Value, error) { return bytesValue(b) }
+
+//----------- EncodedBytes --------------
+
+// Typed forms of Bytes for text and JSON in other encodings (Bytes itself uses hex).
+// They are encoded by Writer as Bytes.
+type (
+	Bytes0x        = EncodedBytes[enc0xHex]     // "0x0102ff"
+	Base64Bytes    = EncodedBytes[encBase64]    // standard base64 with padding
+	Base64URLBytes = EncodedBytes[encBase64URL] // url-safe base64 without padding
+	Base58Bytes    = EncodedBytes[encBase58]    // base58 with bitcoin alphabet
+)
+
+// EncodedBytes is Bytes with text and JSON form in encoding E (see Bytes0x, Base64Bytes, ...)
+type EncodedBytes[E textEncoder] []byte
+
+type (
+	enc0xHex     struct{}
+	encBase64    struct{}
+	encBase64URL struct{}
+	encBase58    struct{}
+)
+
+func (enc0xHex) TextEncoding() TextEncoding     { return Encoding0xHex }
+func (encBase64) TextEncoding() TextEncoding    { return EncodingBase64 }
+func (encBase64URL) TextEncoding() TextEncoding { return EncodingBase64URL }
+func (encBase58) TextEncoding() TextEncoding    { return EncodingBase58 }
+
+func (b EncodedBytes[E]) TextEncoding() TextEncoding {
+	var e E
+	return e.TextEncoding()
+}
+func (b EncodedBytes[E]) String() string               { return Bytes(b).EncodeToString(b.TextEncoding()) }
+func (b EncodedBytes[E]) MarshalText() ([]byte, error) { return []byte(b.String()), nil }
+func (b *EncodedBytes[E]) UnmarshalText(text []byte) error {
+	return unmarshalBytesText((*[]byte)(b), text, b.TextEncoding())
+}
+func (b EncodedBytes[E]) MarshalJSON() ([]byte, error) { return marshalBytesJSON(b, b.TextEncoding()) }
+func (b *EncodedBytes[E]) UnmarshalJSON(data []byte) error {
+	return unmarshalBytesJSON((*[]byte)(b), data, b.TextEncoding())
+}
+func (b *EncodedBytes[E]) Scan(src any) error          { return scanBytes((*[]byte)(b), src) }
+func (b EncodedBytes[E]) Value() (driver.Value, error) { return bytesValue(b) }
+func (b EncodedBytes[E]) BinWrite(w *Writer)           { w.WriteBytes(b) }
+func (b *EncodedBytes[E]) BinRead(r *Reader)           { readBytesTo(r, (*[]byte)(b)) }
@@ -15,3 +15,132 @@ func TestBytes_MarshalJSON(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, `"00000000ffffffff"`, string(data))
 }
+
+func TestBytes_EncodeToString(t *testing.T) {
+	v := Bytes("\x00\x00\xfb\xff\xbf")
+
+	assert.Equal(t, "0000fbffbf", v.EncodeToString(EncodingHex))
+	assert.Equal(t, "0x0000fbffbf", v.EncodeToString(Encoding0xHex))
+	assert.Equal(t, "AAD7/78=", v.EncodeToString(EncodingBase64))
+	assert.Equal(t, "AAD7_78", v.EncodeToString(EncodingBase64URL))
+	assert.Equal(t, "112TeLW", v.EncodeToString(EncodingBase58))
+}
+
+func TestBytes_UnmarshalJSON(t *testing.T) {
+	v := Bytes("\x00\x00\xfb\xff\xbf")
+
+	for _, s := range []string{`"0000fbffbf"`, `"0x0000fbffbf"`, `"AAD7/78="`, `"AAD7_78"`, `"112TeLW"`} {
+		var b Bytes
+		err := json.Unmarshal([]byte(s), &b)
+
+		assert.NoError(t, err, s)
+		assert.Equal(t, v, b, s)
+	}
+	for _, s := range []string{`"0x0g0"`, `"AAD7/78"`, `"0OI!"`, `123`} {
+		var b Bytes
+		err := json.Unmarshal([]byte(s), &b)
+
+		assert.Error(t, err, s)
+	}
+}
+
+func TestBytes_TypedEncodings(t *testing.T) {
+	type Obj struct {
+		A Bytes
+		B Bytes0x
+		C Base64Bytes
+		D Base64URLBytes
+		E Base58Bytes
+	}
+	v := []byte("\x00\x00\xfb\xff\xbf")
+	obj := Obj{v, v, v, v, v}
+
+	data, err := json.Marshal(obj)
+	var res Obj
+	err2 := json.Unmarshal(data, &res)
+
+	assert.NoError(t, err)
+	assert.NoError(t, err2)
+	assert.Equal(t, `{"A":"0000fbffbf","B":"0x0000fbffbf","C":"AAD7/78=","D":"AAD7_78","E":"112TeLW"}`, string(data))
+	assert.Equal(t, obj, res)
+	assert.Equal(t, "StV1DL6CwTryKyV", Base58Bytes("hello world").String())
+}
+
+func TestBytes_UnmarshalJSON_Precedence(t *testing.T) {
+	var b0 Bytes
+	var b1 Base64Bytes
+	var b2, b3, b5 Base58Bytes
+	var b4 Bytes0x
+
+	err0 := json.Unmarshal([]byte(`"deadbeef"`), &b0)
+	err1 := json.Unmarshal([]byte(`"deadbeef"`), &b1)
+	err2 := json.Unmarshal([]byte(`"0x0000fbffbf"`), &b2)
+	err3 := json.Unmarshal([]byte(`"AAD7/78="`), &b3)
+	err4 := json.Unmarshal([]byte(`"0000fbffbf"`), &b4)
+	err5 := b5.UnmarshalText([]byte("11"))
+
+	assert.NoError(t, err0)
+	assert.NoError(t, err1)
+	assert.NoError(t, err2)
+	assert.NoError(t, err3)
+	assert.NoError(t, err4)
+	assert.NoError(t, err5)
+	assert.Equal(t, Bytes{0xde, 0xad, 0xbe, 0xef}, b0)                   // hex
+	assert.Equal(t, Base64Bytes{0x75, 0xe6, 0x9d, 0x6d, 0xe7, 0x9f}, b1) // own encoding first
+	assert.Equal(t, Base58Bytes("\x00\x00\xfb\xff\xbf"), b2)
+	assert.Equal(t, Base58Bytes("\x00\x00\xfb\xff\xbf"), b3) // base64
+	assert.Equal(t, Bytes0x("\x00\x00\xfb\xff\xbf"), b4)
+	assert.Equal(t, Base58Bytes{0, 0}, b5) // not hex
+}
+
+func TestBytes_UnmarshalJSON_Null(t *testing.T) {
+	b := Bytes{1, 2, 3}
+	b2 := Base64Bytes{1, 2, 3}
+
+	err := json.Unmarshal([]byte(`null`), &b)
+	err2 := json.Unmarshal([]byte(`null`), &b2)
+
+	assert.NoError(t, err)
+	assert.NoError(t, err2)
+	assert.Nil(t, b)
+	assert.Nil(t, b2)
+}
+
+func TestBytes_TypedEncodings_Binary(t *testing.T) {
+	v := []byte{1, 2, 3}
+
+	data := Encode(Base64Bytes(v), Base58Bytes(nil))
+	var b1 Base64Bytes
+	var b2 Base58Bytes
+	err := Decode(data, &b1, &b2)
+
+	assert.NoError(t, err)
+	assert.Equal(t, Encode(v, []byte(nil)), data)
+	assert.Equal(t, Base64Bytes(v), b1)
+	assert.Equal(t, encodeMsgPack(v), encodeMsgPack(Base64Bytes(v)))
+	assert.Equal(t, encodeCBOR(v), encodeCBOR(Base64Bytes(v)))
+	assert.NoError(t, decodeMsgPack(encodeMsgPack(v), &b2))
+	assert.Equal(t, Base58Bytes(v), b2)
+}
+
+func TestBytes_SQL(t *testing.T) {
+	src := []byte{1, 2, 3}
+
+	var b Bytes
+	err := b.Scan(src)
+	src[0] = 0
+	v, _ := b.Value()
+
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{1, 2, 3}, v)
+	assert.NoError(t, b.Scan(nil))
+	assert.Nil(t, b)
+	assert.Error(t, b.Scan(123))
+
+	var b2 Base64Bytes
+	err = b2.Scan("abc")
+	v, _ = b2.Value()
+
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("abc"), v)
+}
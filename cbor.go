@@ -0,0 +1,600 @@
+package bin
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"reflect"
+	"sort"
+	"time"
+)
+
+const (
+	cborUint   = 0
+	cborNegInt = 1
+	cborBytes  = 2
+	cborText   = 3
+	cborArray  = 4
+	cborMap    = 5
+	cborTag    = 6
+	cborSimple = 7
+
+	cborFalse = 0xf4
+	cborTrue  = 0xf5
+	cborNull  = 0xf6
+	cborBreak = 0xff
+
+	cborTagTimeString = 0
+	cborTagTimeEpoch  = 1
+	cborTagPosBignum  = 2
+	cborTagNegBignum  = 3
+)
+
+var (
+	errCBORCorrupted    = errors.New("bin.CBOR-Error: invalid data")
+	errCBORDuplicateKey = errors.New("bin.CBOR-Error: duplicate map key")
+)
+
+//----------- CBOR writer --------------
+
+func (w *Writer) writeCBOR(val any) error {
+	switch v := val.(type) {
+	case nil:
+		w.WriteByte(cborNull)
+	case bool:
+		if v {
+			w.WriteByte(cborTrue)
+		} else {
+			w.WriteByte(cborFalse)
+		}
+
+	case int:
+		w.cborInt(int64(v))
+	case int8:
+		w.cborInt(int64(v))
+	case int16:
+		w.cborInt(int64(v))
+	case int32:
+		w.cborInt(int64(v))
+	case int64:
+		w.cborInt(v)
+	case uint:
+		w.cborHead(cborUint, uint64(v))
+	case uint8:
+		w.cborHead(cborUint, uint64(v))
+	case uint16:
+		w.cborHead(cborUint, uint64(v))
+	case uint32:
+		w.cborHead(cborUint, uint64(v))
+	case uint64:
+		w.cborHead(cborUint, v)
+	case float32:
+		w.cborFloat(float64(v))
+	case float64:
+		w.cborFloat(v)
+
+	case string:
+		w.cborHead(cborText, uint64(len(v)))
+		w.Write([]byte(v))
+	case []byte:
+		w.cborBytes(v)
+	case Bytes:
+		w.cborBytes(v)
+
+	case time.Time:
+		w.cborTime(v)
+	case *time.Time:
+		if v == nil {
+			w.WriteByte(cborNull)
+		} else {
+			w.cborTime(*v)
+		}
+	case *big.Int:
+		if v == nil {
+			w.WriteByte(cborNull)
+		} else {
+			w.cborBigInt(v)
+		}
+	case big.Int:
+		w.cborBigInt(&v)
+
+	case RawValue:
+		w.writeRawValue(v)
+
+	case error:
+		w.writeCBOR(v.Error())
+
+	default:
+		rv := reflect.ValueOf(v)
+		if rv.Kind() == reflect.Ptr && rv.IsNil() {
+			w.WriteByte(cborNull)
+			break
+		}
+		if isOpaque(rv.Type()) {
+			if b, err := w.encodeOpaque(v); err != nil {
+				w.SetError(err)
+			} else {
+				w.cborBytes(b)
+			}
+			break
+		}
+		switch rv.Kind() {
+		case reflect.Ptr:
+			w.writeCBOR(rv.Elem().Interface())
+
+		case reflect.Bool:
+			w.writeCBOR(rv.Bool())
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			w.cborInt(rv.Int())
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			w.cborHead(cborUint, rv.Uint())
+		case reflect.Float32, reflect.Float64:
+			w.cborFloat(rv.Float())
+		case reflect.String:
+			w.writeCBOR(rv.String())
+
+		case reflect.Slice, reflect.Array:
+			if rv.Kind() == reflect.Slice && rv.IsNil() {
+				w.WriteByte(cborNull)
+				break
+			}
+			if rv.Type().Elem().Kind() == reflect.Uint8 {
+				b := make([]byte, rv.Len())
+				reflect.Copy(reflect.ValueOf(b), rv)
+				w.cborBytes(b)
+				break
+			}
+			n := rv.Len()
+			w.cborHead(cborArray, uint64(n))
+			for i := 0; i < n && w.err == nil; i++ {
+				w.writeCBOR(rv.Index(i).Interface())
+			}
+
+		case reflect.Map:
+			if rv.IsNil() {
+				w.WriteByte(cborNull)
+				break
+			}
+			var keys, vals []any
+			for it := rv.MapRange(); it.Next(); {
+				keys, vals = append(keys, it.Key().Interface()), append(vals, it.Value().Interface())
+			}
+			w.cborMap(keys, vals)
+
+		case reflect.Struct:
+			if keys, vals := w.structEntries(rv); w.err == nil {
+				w.cborMap(keys, vals)
+			}
+
+		default:
+			w.SetError(fmt.Errorf("bin.CBOR-Error: unsupported type %T", v))
+		}
+	}
+	return w.err
+}
+
+// cborHead writes major type and argument in the shortest form
+func (w *Writer) cborHead(major byte, n uint64) {
+	major <<= 5
+	switch {
+	case n < 24:
+		w.WriteByte(major | byte(n))
+	case n <= math.MaxUint8:
+		w.write([]byte{major | 24, byte(n)})
+	case n <= math.MaxUint16:
+		w.WriteByte(major | 25)
+		w.WriteUint16(uint16(n))
+	case n <= math.MaxUint32:
+		w.WriteByte(major | 26)
+		w.WriteUint32(uint32(n))
+	default:
+		w.WriteByte(major | 27)
+		w.WriteUint64(n)
+	}
+}
+
+func (w *Writer) cborInt(i int64) {
+	if i >= 0 {
+		w.cborHead(cborUint, uint64(i))
+	} else {
+		w.cborHead(cborNegInt, uint64(^i)) // -1-i
+	}
+}
+
+func (w *Writer) cborBytes(b []byte) {
+	if b == nil {
+		w.WriteByte(cborNull)
+		return
+	}
+	w.cborHead(cborBytes, uint64(len(b)))
+	w.Write(b)
+}
+
+// cborFloat writes float in the shortest form that preserves value (half, single or double precision)
+func (w *Writer) cborFloat(f float64) {
+	if f32 := float32(f); float64(f32) == f || f != f {
+		if h := NewFloat16(f32); h.Float32() == f32 || f != f {
+			w.WriteByte(0xf9)
+			if f != f {
+				h = 0x7e00 // canonical NaN
+			}
+			w.WriteUint16(uint16(h))
+			return
+		}
+		w.WriteByte(0xfa)
+		w.WriteFloat32(f32)
+		return
+	}
+	w.WriteByte(0xfb)
+	w.WriteFloat64(f)
+}
+
+// cborTime writes time as epoch-based date/time (tag 1).
+// Time with fractional seconds is written as float64, so precision is about a microsecond.
+func (w *Writer) cborTime(t time.Time) {
+	w.cborHead(cborTag, cborTagTimeEpoch)
+	if t.Nanosecond() == 0 {
+		w.cborInt(t.Unix())
+	} else {
+		w.cborFloat(float64(t.Unix()) + float64(t.Nanosecond())/1e9)
+	}
+}
+
+// cborBigInt writes integer as major type 0 or 1 if possible, otherwise as bignum (tag 2 or 3)
+func (w *Writer) cborBigInt(x *big.Int) {
+	if x.Sign() >= 0 {
+		if x.IsUint64() {
+			w.cborHead(cborUint, x.Uint64())
+		} else {
+			w.cborHead(cborTag, cborTagPosBignum)
+			w.cborBytes(x.Bytes())
+		}
+		return
+	}
+	n := new(big.Int).Not(x) // -1-x
+	if n.IsUint64() {
+		w.cborHead(cborNegInt, n.Uint64())
+	} else {
+		w.cborHead(cborTag, cborTagNegBignum)
+		w.cborBytes(n.Bytes())
+	}
+}
+
+// cborMap writes map with keys sorted by their encoding (RFC 8949 core deterministic encoding)
+func (w *Writer) cborMap(keys, vals []any) {
+	type pair struct{ key, val []byte }
+	pairs := make([]pair, len(keys))
+	for i := range keys {
+		var kb, vb bytes.Buffer
+		kw := Writer{wr: &kb, mode: w.mode, format: FormatCBOR}
+		vw := Writer{wr: &vb, mode: w.mode, format: FormatCBOR}
+		if kw.writeCBOR(keys[i]) != nil || vw.writeCBOR(vals[i]) != nil {
+			w.SetError(errors.Join(kw.err, vw.err))
+			return
+		}
+		pairs[i] = pair{kb.Bytes(), vb.Bytes()}
+	}
+	sort.Slice(pairs, func(i, j int) bool { return bytes.Compare(pairs[i].key, pairs[j].key) < 0 })
+	for i := 1; i < len(pairs); i++ {
+		if bytes.Equal(pairs[i-1].key, pairs[i].key) {
+			w.SetError(errCBORDuplicateKey)
+			return
+		}
+	}
+	w.cborHead(cborMap, uint64(len(pairs)))
+	for _, p := range pairs {
+		w.Write(p.key)
+		w.Write(p.val)
+	}
+}
+
+//----------- CBOR reader --------------
+
+func (r *Reader) readCBORVar(val any) error {
+	p := reflect.ValueOf(val)
+	if p.Kind() != reflect.Ptr || p.IsNil() {
+		r.SetError(errIncompatibleType)
+		return r.err
+	}
+	v, err := r.readCBOR()
+	if err == nil {
+		r.SetError(r.setDecoded(p.Elem(), v))
+	}
+	return r.err
+}
+
+// readCBOR reads any CBOR data item
+func (r *Reader) readCBOR() (any, error) {
+	b, err := r.ReadByte()
+	if err != nil {
+		return nil, err
+	}
+	if b == cborBreak {
+		r.SetError(errCBORCorrupted)
+		return nil, r.err
+	}
+	return r.cborItem(b)
+}
+
+func (r *Reader) cborItem(b byte) (any, error) {
+	defer r.leave()
+	if r.enter(errCBORCorrupted) != nil {
+		return nil, r.err
+	}
+	major, info := b>>5, b&0x1f
+	if major == cborSimple {
+		return r.cborSimple(info)
+	}
+	if info == 31 { // indefinite length
+		return r.cborIndefinite(major)
+	}
+	n := r.cborArg(info)
+	if r.err != nil {
+		return nil, r.err
+	}
+	switch major {
+	case cborUint:
+		if n > math.MaxInt64 {
+			return n, nil
+		}
+		return int64(n), nil
+
+	case cborNegInt:
+		if n > math.MaxInt64 {
+			return new(big.Int).Not(new(big.Int).SetUint64(n)), nil
+		}
+		return -1 - int64(n), nil
+
+	case cborBytes:
+		return r.read(r.cborLen(n))
+
+	case cborText:
+		b, err := r.read(r.cborLen(n))
+		return string(b), err
+
+	// length comes from input, so arrays and maps grow as items are read
+	case cborArray:
+		size := r.cborLen(n)
+		arr := make([]any, 0, preallocLen(size, 16))
+		for i := 0; i < size && r.err == nil; i++ {
+			v, _ := r.readCBOR()
+			arr = append(arr, v)
+		}
+		return arr, r.err
+
+	case cborMap:
+		size := r.cborLen(n)
+		keys, vals := make([]any, 0, preallocLen(size, 32)), make([]any, 0, preallocLen(size, 32))
+		for i := 0; i < size && r.err == nil; i++ {
+			k, _ := r.readCBOR()
+			v, _ := r.readCBOR()
+			keys, vals = append(keys, k), append(vals, v)
+		}
+		return r.cborMap(keys, vals)
+
+	default: // tag
+		v, err := r.readCBOR()
+		if err != nil {
+			return nil, err
+		}
+		return r.cborTagged(n, v)
+	}
+}
+
+// cborArg reads argument of data item
+func (r *Reader) cborArg(info byte) uint64 {
+	switch {
+	case info < 24:
+		return uint64(info)
+	case info == 24:
+		u, _ := r.ReadUint8()
+		return uint64(u)
+	case info == 25:
+		u, _ := r.ReadUint16()
+		return uint64(u)
+	case info == 26:
+		u, _ := r.ReadUint32()
+		return uint64(u)
+	case info == 27:
+		u, _ := r.ReadUint64()
+		return u
+	}
+	r.SetError(errCBORCorrupted)
+	return 0
+}
+
+func (r *Reader) cborLen(n uint64) int {
+	if n > math.MaxInt32 {
+		r.SetError(errCBORCorrupted)
+		return 0
+	}
+	return int(n)
+}
+
+func (r *Reader) cborSimple(info byte) (any, error) {
+	switch info {
+	case 20:
+		return false, nil
+	case 21:
+		return true, nil
+	case 22, 23: // null, undefined
+		return nil, nil
+	case 25:
+		u, err := r.ReadUint16()
+		return Float16(u).Float32(), err
+	case 26:
+		return r.ReadFloat32()
+	case 27:
+		return r.ReadFloat64()
+	}
+	r.SetError(errCBORCorrupted)
+	return nil, r.err
+}
+
+// cborIndefinite reads indefinite-length string, array or map
+func (r *Reader) cborIndefinite(major byte) (any, error) {
+	var items []any
+	for r.err == nil {
+		b, err := r.ReadByte()
+		if err != nil {
+			break
+		}
+		if b == cborBreak {
+			break
+		}
+		if (major == cborBytes || major == cborText) && (b>>5 != major || b&0x1f == 31) {
+			r.SetError(errCBORCorrupted) // chunks must be definite strings of the same type
+			break
+		}
+		v, _ := r.cborItem(b)
+		items = append(items, v)
+	}
+	if r.err != nil {
+		return nil, r.err
+	}
+	switch major {
+	case cborBytes, cborText:
+		var buf []byte
+		for _, v := range items {
+			switch s := v.(type) {
+			case []byte:
+				buf = append(buf, s...)
+			case string:
+				buf = append(buf, s...)
+			}
+		}
+		if major == cborText {
+			return string(buf), nil
+		}
+		return append([]byte{}, buf...), nil
+	case cborArray:
+		return append([]any{}, items...), nil
+	case cborMap:
+		if len(items)%2 != 0 {
+			r.SetError(errCBORCorrupted)
+			return nil, r.err
+		}
+		var keys, vals []any
+		for i := 0; i < len(items); i += 2 {
+			keys, vals = append(keys, items[i]), append(vals, items[i+1])
+		}
+		return r.cborMap(keys, vals)
+	}
+	r.SetError(errCBORCorrupted)
+	return nil, r.err
+}
+
+func (r *Reader) cborMap(keys, vals []any) (any, error) {
+	if r.err != nil {
+		return nil, r.err
+	}
+	m, err := newDecodedMap(keys, vals)
+	if err == nil && reflect.ValueOf(m).Len() != len(keys) {
+		m, err = nil, errCBORDuplicateKey
+	}
+	r.SetError(err)
+	return m, err
+}
+
+func (r *Reader) cborTagged(tag uint64, v any) (any, error) {
+	switch tag {
+	case cborTagTimeString:
+		if s, ok := v.(string); ok {
+			t, err := time.Parse(time.RFC3339Nano, s)
+			r.SetError(err)
+			return t, err
+		}
+	case cborTagTimeEpoch:
+		switch x := v.(type) {
+		case int64:
+			return time.Unix(x, 0), nil
+		case float32:
+			return cborEpochTime(float64(x)), nil
+		case float64:
+			return cborEpochTime(x), nil
+		}
+	case cborTagPosBignum, cborTagNegBignum:
+		if b, ok := v.([]byte); ok {
+			x := new(big.Int).SetBytes(b)
+			if tag == cborTagNegBignum {
+				x.Not(x)
+			}
+			return x, nil
+		}
+	default:
+		return v, nil // unknown tags are ignored
+	}
+	r.SetError(errCBORCorrupted)
+	return nil, r.err
+}
+
+// cborEpochTime converts float epoch-based time to time rounded to microseconds
+func cborEpochTime(f float64) time.Time {
+	sec := math.Floor(f)
+	return time.Unix(int64(sec), int64(math.Round((f-sec)*1e6))*1e3)
+}
+
+// skipCBOR skips next CBOR data item
+func (r *Reader) skipCBOR() error {
+	b, err := r.ReadByte()
+	if err != nil {
+		return err
+	}
+	if b == cborBreak {
+		r.SetError(errCBORCorrupted)
+		return r.err
+	}
+	return r.skipCBORItem(b)
+}
+
+func (r *Reader) skipCBORItem(b byte) error {
+	defer r.leave()
+	if r.enter(errCBORCorrupted) != nil {
+		return r.err
+	}
+	major, info := b>>5, b&0x1f
+	if major == cborSimple {
+		switch {
+		case info < 24:
+			return nil
+		case info <= 27:
+			return r.discard(1 << (info - 24))
+		}
+		r.SetError(errCBORCorrupted)
+		return r.err
+	}
+	if info == 31 { // indefinite length: items until break
+		if major < cborBytes || major == cborTag {
+			r.SetError(errCBORCorrupted)
+		}
+		for r.err == nil {
+			if b, err := r.ReadByte(); err != nil || b == cborBreak {
+				break
+			} else if (major == cborBytes || major == cborText) && (b>>5 != major || b&0x1f == 31) {
+				r.SetError(errCBORCorrupted) // chunks must be definite strings of the same type
+			} else {
+				r.skipCBORItem(b)
+			}
+		}
+		return r.err
+	}
+	n := r.cborArg(info)
+	switch major {
+	case cborBytes, cborText:
+		return r.discard(r.cborLen(n))
+	case cborArray:
+		n = uint64(r.cborLen(n))
+	case cborMap:
+		n = 2 * uint64(r.cborLen(n))
+	case cborTag:
+		n = 1
+	default:
+		return r.err
+	}
+	for i := uint64(0); i < n && r.err == nil; i++ {
+		r.skipCBOR()
+	}
+	return r.err
+}
@@ -0,0 +1,248 @@
+package bin
+
+import (
+	"encoding/hex"
+	"math"
+	"math/big"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func encodeCBOR(values ...any) []byte {
+	w := NewBuffer(nil)
+	w.SetFormat(FormatCBOR)
+	w.WriteVar(values...)
+	return w.Bytes()
+}
+
+func decodeCBOR(data []byte, values ...any) error {
+	r := NewBuffer(data)
+	r.SetFormat(FormatCBOR)
+	return r.ReadVar(values...)
+}
+
+func TestCBOR_encoding(t *testing.T) { // RFC 8949, Appendix A
+	bigPos, _ := new(big.Int).SetString("18446744073709551616", 10)
+	bigNeg, _ := new(big.Int).SetString("-18446744073709551617", 10)
+	negMax, _ := new(big.Int).SetString("-18446744073709551616", 10)
+
+	for _, c := range []struct {
+		v   any
+		exp string
+	}{
+		{0, "00"},
+		{23, "17"},
+		{24, "1818"},
+		{1000, "1903e8"},
+		{1000000000000, "1b000000e8d4a51000"},
+		{uint64(math.MaxUint64), "1bffffffffffffffff"},
+		{bigPos, "c249010000000000000000"},
+		{negMax, "3bffffffffffffffff"},
+		{bigNeg, "c349010000000000000000"},
+		{big.NewInt(-1), "20"},
+		{-1000, "3903e7"},
+		{0.0, "f90000"},
+		{math.Copysign(0, -1), "f98000"},
+		{1.0, "f93c00"},
+		{1.1, "fb3ff199999999999a"},
+		{float32(1.5), "f93e00"},
+		{65504.0, "f97bff"},
+		{100000.0, "fa47c35000"},
+		{3.4028234663852886e+38, "fa7f7fffff"},
+		{1.0e+300, "fb7e37e43c8800759c"},
+		{5.960464477539063e-8, "f90001"},
+		{-4.1, "fbc010666666666666"},
+		{math.Inf(1), "f97c00"},
+		{math.NaN(), "f97e00"},
+		{math.Inf(-1), "f9fc00"},
+		{false, "f4"},
+		{nil, "f6"},
+		{time.Unix(1363896240, 0), "c11a514b67b0"},
+		{time.Unix(1363896240, 5e8), "c1fb41d452d9ec200000"},
+		{[]byte{}, "40"},
+		{Bytes{1, 2, 3, 4}, "4401020304"},
+		{"", "60"},
+		{"ü", "62c3bc"},
+		{[]int{}, "80"},
+		{[]int{1, 2, 3}, "83010203"},
+		{map[int]int{3: 4, 1: 2}, "a201020304"},
+		{map[string]any{"b": []int{2, 3}, "a": 1}, "a26161016162820203"},
+		{map[any]int{"b": 1, "a": 2, 10: 3, -1: 4}, "a40a032004616102616201"},
+	} {
+		assert.Equal(t, c.exp, hex.EncodeToString(encodeCBOR(c.v)), c.v)
+	}
+}
+
+func TestCBOR_decoding(t *testing.T) {
+	for _, c := range []struct {
+		data string
+		exp  any
+	}{
+		{"1bffffffffffffffff", uint64(math.MaxUint64)},
+		{"3863", int64(-100)},
+		{"f93e00", float32(1.5)},
+		{"f97c00", float32(math.Inf(1))},
+		{"f5", true},
+		{"f7", nil},
+		{"c074323031332d30332d32315432303a30343a30305a", time.Date(2013, 3, 21, 20, 4, 0, 0, time.UTC)},
+		{"c11a514b67b0", time.Unix(1363896240, 0)},
+		{"c1f93e00", time.Unix(1, 5e8)},
+		{"d74401020304", []byte{1, 2, 3, 4}}, // unknown tag
+		{"5f42010243030405ff", []byte{1, 2, 3, 4, 5}},
+		{"7f657374726561646d696e67ff", "streaming"},
+		{"9fff", []any{}},
+		{"bf61610161629f0203ffff", map[string]any{"a": int64(1), "b": []any{int64(2), int64(3)}}},
+		{"a201020304", map[any]any{int64(1): int64(2), int64(3): int64(4)}},
+	} {
+		data, _ := hex.DecodeString(c.data)
+		var v any
+		err := decodeCBOR(data, &v)
+
+		assert.NoError(t, err, c.data)
+		assert.Equal(t, c.exp, v, c.data)
+	}
+}
+
+func TestCBOR_values(t *testing.T) {
+	type Obj struct {
+		testHeader
+		Name  string
+		Amt   *big.Int
+		Hash  H160
+		Parts []testPart
+		Tags  map[string]int
+		Score float64
+		Opt   Optional[string]
+	}
+	bi, _ := new(big.Int).SetString("-123456789012345678901234567890", 10)
+	obj := Obj{
+		testHeader: testHeader{ID: 1, CreatedAt: time.Unix(1700000000, 123456000)},
+		Name:       "obj",
+		Amt:        bi,
+		Hash:       HashH160("abc"),
+		Parts:      []testPart{{"a", 1}},
+		Tags:       map[string]int{"x": 1, "y": -2},
+		Score:      0.1,
+		Opt:        Some("opt"),
+	}
+
+	data := encodeCBOR(obj, []string(nil), uint8(200))
+	var (
+		res Obj
+		ss  = []string{"a"}
+		u   uint8
+	)
+	err := decodeCBOR(data, &res, &ss, &u)
+
+	assert.NoError(t, err)
+	assert.Equal(t, obj.Amt.String(), res.Amt.String())
+	res.Amt = obj.Amt
+	assert.Equal(t, obj, res)
+	assert.Nil(t, ss)
+	assert.Equal(t, uint8(200), u)
+}
+
+func TestCBOR_deterministic(t *testing.T) {
+	m1 := map[string]any{}
+	m2 := map[string]any{}
+	for i := 0; i < 100; i++ {
+		m1[string(rune('a'+i%26))+string(rune('0'+i/26))] = i
+	}
+	for k, v := range m1 {
+		m2[k] = v
+	}
+
+	assert.Equal(t, encodeCBOR(m1), encodeCBOR(m2))
+	assert.Equal(t, HashCBOR256(m1), HashCBOR256(m2))
+	assert.NotEqual(t, HashCBOR256(m1), Hash256(m1))
+}
+
+func TestCBOR_uniqueKeys(t *testing.T) {
+	type Obj struct {
+		testHeader
+		ID   string // hides testHeader.ID
+		Name string
+	}
+	obj := Obj{testHeader{1, time.Unix(1700000000, 0)}, "id", "obj"}
+
+	data := encodeCBOR(obj)
+	var m map[string]any
+	err := decodeCBOR(data, &m)
+
+	w := NewBuffer(nil)
+	w.SetFormat(FormatCBOR)
+	err2 := w.WriteVar(map[any]int{1: 1, int64(1): 2}) // keys with the same encoding
+
+	assert.NoError(t, err)
+	assert.Equal(t, byte(0xa3), data[0])
+	assert.Equal(t, []string{"ID", "Name", "CreatedAt"}, cborKeys(data))
+	assert.ErrorIs(t, err2, errCBORDuplicateKey)
+	assert.ErrorIs(t, decodeCBOR([]byte{0xa2, 0x61, 'a', 0x01, 0x61, 'a', 0x02}, &m), errCBORDuplicateKey)
+}
+
+// cborKeys returns text keys of encoded CBOR map in order of encoding
+func cborKeys(data []byte) (keys []string) {
+	r := NewBuffer(data[1:])
+	r.SetFormat(FormatCBOR)
+	for i := 0; i < int(data[0]&0x1f); i++ {
+		var k string
+		r.ReadVar(&k)
+		r.SkipVar(nil)
+		keys = append(keys, k)
+	}
+	return
+}
+
+func TestHasher_SetFormat(t *testing.T) {
+	h := NewHasher(nil)
+	h.SetFormat(FormatCBOR)
+	h.Add(map[string]int{"a": 1})
+	h.Reset()
+	h.Add(map[string]int{"a": 1})
+
+	assert.Equal(t, HashCBOR256(map[string]int{"a": 1}), h.Sum256())
+}
+
+func TestCBOR_errors(t *testing.T) {
+	var a any
+	var i int8
+
+	for _, data := range []string{"ff", "1c", "62c3", "5f01ff", "c2f4", "9f01", "f8",
+		"5f5f4101ffff", "7f7f6161ffff", "7f4161ff", // nested indefinite or mismatched chunks
+	} {
+		b, _ := hex.DecodeString(data)
+		assert.Error(t, decodeCBOR(b, &a), data)
+
+		r := NewBuffer(b)
+		r.SetFormat(FormatCBOR)
+		if data != "c2f4" { // valid item of unexpected type
+			assert.Error(t, r.SkipVar(nil), data)
+		}
+	}
+	assert.Error(t, decodeCBOR([]byte{0x19, 0x01, 0x00}, &i))
+}
+
+func TestCBOR_corruptedLengths(t *testing.T) {
+	var ms runtime.MemStats
+	runtime.ReadMemStats(&ms)
+	alloc := ms.TotalAlloc
+
+	for _, data := range []string{"5affffffff", "7affffffff", "9affffffff", "baffffffff",
+		"5b00000000ffffffff", "9b00000000ffffffff", "5bffffffffffffffff", "9a7ffffffff6f6", "ba7ffffffff6f6"} {
+		b, _ := hex.DecodeString(data)
+		var a any
+		err := decodeCBOR(b, &a)
+
+		r := NewBuffer(b)
+		r.SetFormat(FormatCBOR)
+		err2 := r.SkipVar(nil)
+
+		assert.Error(t, err, data)
+		assert.Error(t, err2, data)
+	}
+	runtime.ReadMemStats(&ms)
+	assert.Less(t, ms.TotalAlloc-alloc, uint64(10<<20)) // lengths are not allocated up front
+}
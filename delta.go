@@ -0,0 +1,108 @@
+package bin
+
+import (
+	"strings"
+	"time"
+)
+
+// DeltaInts, DeltaUints, DeltaTimes are slices encoded as length, first value and varint deltas
+// between adjacent values. Encoding is efficient for sorted or slowly changing sequences.
+// Times are encoded as two sequences: Unix seconds and nanoseconds (any time including zero time round-trips).
+// Location of the first time is written once (as in WriteTimeFull) and is set to all decoded times.
+//
+// Delta2Ints, Delta2Times are encoded with delta-of-delta (differences between adjacent deltas),
+// so regular sequences (time series with fixed interval) take about one byte per value (two bytes per time).
+//
+// Struct fields of types []int64, []uint64, []time.Time can be encoded the same way
+// with tag `bin:",delta"` or `bin:",delta2"` (ModeStructs).
+type (
+	DeltaInts   []int64
+	DeltaUints  []uint64
+	DeltaTimes  []time.Time
+	Delta2Ints  []int64
+	Delta2Times []time.Time
+)
+
+func (v DeltaInts) BinWrite(w *Writer)   { w.WriteDeltaInts(v) }
+func (v DeltaUints) BinWrite(w *Writer)  { w.WriteDeltaUints(v) }
+func (v DeltaTimes) BinWrite(w *Writer)  { w.WriteDeltaTimes(v) }
+func (v Delta2Ints) BinWrite(w *Writer)  { w.WriteDelta2Ints(v) }
+func (v Delta2Times) BinWrite(w *Writer) { w.WriteDelta2Times(v) }
+
+func (v *DeltaInts) BinRead(r *Reader)   { *v, _ = r.ReadDeltaInts() }
+func (v *DeltaUints) BinRead(r *Reader)  { *v, _ = r.ReadDeltaUints() }
+func (v *DeltaTimes) BinRead(r *Reader)  { *v, _ = r.ReadDeltaTimes() }
+func (v *Delta2Ints) BinRead(r *Reader)  { *v, _ = r.ReadDelta2Ints() }
+func (v *Delta2Times) BinRead(r *Reader) { *v, _ = r.ReadDelta2Times() }
+
+// toDeltas replaces values with deltas of given order (in place)
+func toDeltas(vv []int64, order int) {
+	for k := 0; k < order; k++ {
+		for i := len(vv) - 1; i > k; i-- {
+			vv[i] -= vv[i-1]
+		}
+	}
+}
+
+// fromDeltas restores values from deltas of given order (in place)
+func fromDeltas(vv []int64, order int) {
+	for k := order - 1; k >= 0; k-- {
+		for i := k + 1; i < len(vv); i++ {
+			vv[i] += vv[i-1]
+		}
+	}
+}
+
+func uintsToInts(vv []uint64) []int64 {
+	res := make([]int64, len(vv))
+	for i, v := range vv {
+		res[i] = int64(v)
+	}
+	return res
+}
+
+func intsToUints(vv []int64) []uint64 {
+	if vv == nil {
+		return nil
+	}
+	res := make([]uint64, len(vv))
+	for i, v := range vv {
+		res[i] = uint64(v)
+	}
+	return res
+}
+
+// withDeltaOpts converts value (or pointer) of struct field to delta type according to tag options
+func withDeltaOpts(v any, opts string) any {
+	for _, opt := range strings.Split(opts, ",") {
+		switch opt {
+		case "delta":
+			switch x := v.(type) {
+			case []int64:
+				return DeltaInts(x)
+			case []uint64:
+				return DeltaUints(x)
+			case []time.Time:
+				return DeltaTimes(x)
+			case *[]int64:
+				return (*DeltaInts)(x)
+			case *[]uint64:
+				return (*DeltaUints)(x)
+			case *[]time.Time:
+				return (*DeltaTimes)(x)
+			}
+		case "delta2":
+			switch x := v.(type) {
+			case []int64:
+				return Delta2Ints(x)
+			case []time.Time:
+				return Delta2Times(x)
+			case *[]int64:
+				return (*Delta2Ints)(x)
+			case *[]time.Time:
+				return (*Delta2Times)(x)
+			}
+		}
+	}
+	return v
+}
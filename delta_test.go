@@ -0,0 +1,151 @@
+package bin
+
+import (
+	"math"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeltaInts(t *testing.T) {
+	ids := DeltaInts{1000000, 1000001, 1000005, 1000100, -5, math.MaxInt64, math.MinInt64}
+	uids := DeltaUints{1 << 40, 1<<40 + 1, 3, math.MaxUint64}
+
+	data := Encode(ids, uids, DeltaInts{}, DeltaInts(nil))
+	var (
+		res  DeltaInts
+		ures DeltaUints
+		e1   = DeltaInts{1}
+		e2   DeltaInts
+	)
+	err := Decode(data, &res, &ures, &e1, &e2)
+
+	assert.NoError(t, err)
+	assert.Equal(t, ids, res)
+	assert.Equal(t, uids, ures)
+	assert.Nil(t, e1)
+	assert.Nil(t, e2)
+}
+
+func TestDeltaInts_size(t *testing.T) {
+	ids := make(DeltaInts, 100)
+	for i := range ids {
+		ids[i] = 1e12 + int64(i)*3
+	}
+
+	assert.Equal(t, len(Encode(100, ids[0]))+99, len(Encode(ids)))
+	assert.Equal(t, len(Encode(100, ids[0]))+99, len(Encode(Delta2Ints(ids))))
+}
+
+func TestDelta2Times(t *testing.T) {
+	t0 := time.Unix(1700000000, 0)
+	tt := make(Delta2Times, 1000)
+	for i := range tt {
+		tt[i] = t0.Add(time.Duration(i) * time.Second)
+	}
+	tt[500] = tt[500].Add(time.Millisecond) // jitter
+
+	data := Encode(tt)
+	var res Delta2Times
+	err := Decode(data, &res)
+
+	assert.NoError(t, err)
+	assert.Equal(t, tt, res)
+	assert.Less(t, len(data), 2*1000+30)
+	assert.Equal(t, len(Encode(1000))+8000, len(Encode([]time.Time(tt))))
+}
+
+func TestDeltaTimes_Range(t *testing.T) {
+	loc := time.FixedZone("X", 5*3600)
+	tt := DeltaTimes{{}, time.Date(3000, 1, 1, 0, 0, 0, 1, time.UTC), time.Date(1000, 1, 1, 0, 0, 0, 0, time.UTC), {}}
+	tt2 := Delta2Times{time.Date(2500, 6, 1, 12, 0, 0, 999999999, loc), {}, time.Date(1600, 1, 1, 0, 0, 0, 0, loc)}
+
+	data := Encode(tt, tt2, "tail")
+	var res DeltaTimes
+	var res2 Delta2Times
+	var s string
+	err := Decode(data, &res, &res2, &s)
+	r := NewBuffer(data)
+	err2 := Skip[DeltaTimes](&r.Reader)
+	err3 := Skip[Delta2Times](&r.Reader)
+	s2, _ := r.ReadString()
+
+	assert.NoError(t, err)
+	assert.NoError(t, err2)
+	assert.NoError(t, err3)
+	assert.Equal(t, tt, res)
+	assert.True(t, res[0].IsZero())
+	assert.Equal(t, time.Time{}, res2[1])
+	assert.True(t, tt2[0].Equal(res2[0]))
+	assert.True(t, tt2[2].Equal(res2[2]))
+	assert.Equal(t, tt2[0].Format(time.RFC3339Nano), res2[0].Format(time.RFC3339Nano))
+	assert.Equal(t, "tail", s)
+	assert.Equal(t, "tail", s2)
+}
+
+func TestDeltaInts_CorruptedLength(t *testing.T) {
+	for _, n := range []int64{1 << 20, 1 << 61, math.MaxInt64} {
+		var v DeltaInts
+		var v2 DeltaTimes
+		err := Decode(Encode(n, 1), &v)
+		err2 := Decode(Encode(n, timeUTC, 1), &v2)
+
+		assert.Error(t, err, n)
+		assert.Error(t, err2, n)
+	}
+}
+
+func TestDelta_structTags(t *testing.T) {
+	type Series struct {
+		IDs    []int64     `bin:",delta"`
+		Counts []uint64    `bin:",delta"`
+		Times  []time.Time `bin:",delta2"`
+		Values []int64
+	}
+	t0 := time.Unix(1700000000, 0)
+	s := Series{
+		IDs:    []int64{100, 101, 102},
+		Counts: []uint64{5, 10, 15},
+		Times:  []time.Time{t0, t0.Add(time.Minute), t0.Add(2 * time.Minute)},
+		Values: []int64{100, 101, 102},
+	}
+	w := NewBuffer(nil)
+	w.SetMode(ModeStructs)
+
+	w.WriteVar(s)
+	data := append([]byte{}, w.Bytes()...)
+	var res Series
+	err := w.ReadVar(&res)
+
+	assert.NoError(t, err)
+	assert.Equal(t, s, res)
+	assert.Equal(t, Encode(DeltaInts(s.IDs), DeltaUints(s.Counts), Delta2Times(s.Times), s.Values), data)
+}
+
+func TestDelta_Limits(t *testing.T) {
+	values := []any{
+		DeltaInts{math.MaxInt64, -1},
+		DeltaInts{math.MinInt64, math.MaxInt64, 0, math.MinInt64},
+		Delta2Ints{0, 1 << 62, 0},
+		Delta2Ints{math.MinInt64, math.MaxInt64, math.MinInt64, 0},
+		DeltaUints{math.MaxUint64, 0, 1 << 63},
+	}
+	for _, mode := range []Mode{0, ModeLEB128} {
+		for _, v := range values {
+			w := NewBuffer(nil)
+			w.SetMode(mode)
+			w.WriteVar(v, "tail")
+
+			res := reflect.New(reflect.TypeOf(v))
+			var tail string
+			err := w.ReadVar(res.Interface(), &tail)
+
+			assert.NoError(t, err, v)
+			assert.Equal(t, v, res.Elem().Interface(), v)
+			assert.Equal(t, "tail", tail)
+		}
+	}
+	assert.Equal(t, []byte{0xc8, 0x80, 0, 0, 0, 0, 0, 0, 0}, Encode(int64(math.MinInt64)))
+}
@@ -0,0 +1,92 @@
+package bin
+
+import (
+	"math"
+	"strconv"
+)
+
+// Float16 is IEEE 754 half-precision floating-point number
+type Float16 uint16
+
+// BFloat16 is brain floating-point number (upper 16 bits of float32)
+type BFloat16 uint16
+
+// NewFloat16 converts float32 to Float16 with rounding to nearest even
+func NewFloat16(f float32) Float16 {
+	b := math.Float32bits(f)
+	sign := uint16(b>>16) & 0x8000
+	exp := int(b>>23) & 0xff
+	mant := b & 0x7fffff
+
+	if exp == 0xff { // Inf or NaN
+		if mant != 0 {
+			return Float16(sign | 0x7e00 | uint16(mant>>13))
+		}
+		return Float16(sign | 0x7c00)
+	}
+	e := exp - 127 + 15
+	if e >= 0x1f { // overflow
+		return Float16(sign | 0x7c00)
+	}
+	if e <= 0 { // subnormal or zero
+		if e < -10 {
+			return Float16(sign)
+		}
+		mant |= 0x800000
+		shift := uint(14 - e)
+		h := mant >> shift
+		rem, half := mant&(1<<shift-1), uint32(1)<<(shift-1)
+		if rem > half || rem == half && h&1 == 1 {
+			h++
+		}
+		return Float16(sign | uint16(h))
+	}
+	h := uint32(e)<<10 | mant>>13
+	if rem := mant & 0x1fff; rem > 0x1000 || rem == 0x1000 && h&1 == 1 {
+		h++ // carry to exponent is correct (up to Inf)
+	}
+	return Float16(sign | uint16(h))
+}
+
+func (h Float16) Float32() float32 {
+	sign := uint32(h&0x8000) << 16
+	exp := uint32(h>>10) & 0x1f
+	mant := uint32(h & 0x3ff)
+	switch exp {
+	case 0:
+		if mant == 0 {
+			return math.Float32frombits(sign)
+		}
+		// subnormal - normalize
+		e := uint32(127 - 15 + 1)
+		for mant&0x400 == 0 {
+			mant <<= 1
+			e--
+		}
+		return math.Float32frombits(sign | e<<23 | (mant&0x3ff)<<13)
+	case 0x1f:
+		return math.Float32frombits(sign | 0x7f800000 | mant<<13)
+	}
+	return math.Float32frombits(sign | (exp+127-15)<<23 | mant<<13)
+}
+
+func (h Float16) String() string {
+	return strconv.FormatFloat(float64(h.Float32()), 'g', -1, 32)
+}
+
+// NewBFloat16 converts float32 to BFloat16 with rounding to nearest even
+func NewBFloat16(f float32) BFloat16 {
+	b := math.Float32bits(f)
+	if b&0x7fffffff > 0x7f800000 { // NaN
+		return BFloat16(b>>16 | 0x40)
+	}
+	return BFloat16((b + 0x7fff + (b>>16)&1) >> 16)
+}
+
+func (h BFloat16) Float32() float32 {
+	return math.Float32frombits(uint32(h) << 16)
+}
+
+func (h BFloat16) String() string {
+	return strconv.FormatFloat(float64(h.Float32()), 'g', -1, 32)
+}
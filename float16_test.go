@@ -0,0 +1,95 @@
+package bin
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewFloat16(t *testing.T) {
+	for _, c := range []struct {
+		f float32
+		h Float16
+	}{
+		{0, 0},
+		{float32(math.Copysign(0, -1)), 0x8000},
+		{1, 0x3c00},
+		{-2, 0xc000},
+		{0.1, 0x2e66},
+		{1. / 3, 0x3555},
+		{65504, 0x7bff},
+		{65519, 0x7bff},
+		{65520, 0x7c00}, // rounded to +Inf
+		{float32(math.Inf(-1)), 0xfc00},
+		{6.1035156e-05, 0x0400}, // min normal
+		{5.9604645e-08, 0x0001}, // min subnormal
+		{2.9802322e-08, 0x0000}, // half of min subnormal, rounded to even
+		{2.9802326e-08, 0x0001}, // above half of min subnormal
+		{1.0009765625, 0x3c01},  // exact
+		{1.00048828125, 0x3c00}, // tie, rounded to even
+		{1.00146484375, 0x3c02}, // tie, rounded to even
+		{6.0975552e-05, 0x03ff}, // max subnormal
+		{6.1005353e-05, 0x0400}, // rounded up from subnormal to normal
+	} {
+		assert.Equal(t, c.h, NewFloat16(c.f), "%v", c.f)
+	}
+	assert.True(t, math.IsNaN(float64(NewFloat16(float32(math.NaN())).Float32())))
+}
+
+func TestFloat16_Float32(t *testing.T) {
+	for i := 0; i < 0x10000; i++ {
+		h := Float16(i)
+		f := h.Float32()
+		if math.IsNaN(float64(f)) {
+			assert.True(t, h&0x7c00 == 0x7c00 && h&0x3ff != 0)
+			continue
+		}
+		assert.Equal(t, h, NewFloat16(f), "%x", i)
+	}
+	assert.Equal(t, "0.33325195", NewFloat16(1./3).String())
+}
+
+func TestNewBFloat16(t *testing.T) {
+	assert.Equal(t, BFloat16(0x3f80), NewBFloat16(1))
+	assert.Equal(t, BFloat16(0x4049), NewBFloat16(math.Pi))
+	assert.Equal(t, BFloat16(0x7f80), NewBFloat16(float32(math.Inf(1))))
+	assert.Equal(t, float32(3.140625), NewBFloat16(math.Pi).Float32())
+	assert.True(t, math.IsNaN(float64(NewBFloat16(float32(math.NaN())).Float32())))
+	for i := 0; i < 0x10000; i++ {
+		if h := BFloat16(i); h&0x7f80 != 0x7f80 {
+			assert.Equal(t, h, NewBFloat16(h.Float32()))
+		}
+	}
+}
+
+func TestFloat16_Encode(t *testing.T) {
+	vec := []Float16{NewFloat16(0.5), NewFloat16(-1), NewFloat16(0.1)}
+	bvec := []BFloat16{NewBFloat16(0.5), NewBFloat16(-1)}
+
+	data := Encode(NewFloat16(1), vec, bvec)
+	var (
+		f  Float16
+		v  []Float16
+		bv []BFloat16
+	)
+	err := Decode(data, &f, &v, &bv)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{0x3c, 0x00, 3, 0x38, 0x00, 0xbc, 0x00, 0x2e, 0x66, 2, 0x3f, 0x00, 0xbf, 0x80}, data)
+	assert.Equal(t, NewFloat16(1), f)
+	assert.Equal(t, vec, v)
+	assert.Equal(t, bvec, bv)
+}
+
+func TestFloat16_Decode_CorruptedLength(t *testing.T) {
+	for _, n := range []int64{1 << 20, 1 << 61, math.MaxInt64} {
+		var v []Float16
+		var v2 []BFloat16
+		err := Decode(Encode(n), &v)
+		err2 := Decode(Encode(n, 1), &v2)
+
+		assert.Error(t, err, n)
+		assert.Error(t, err2, n)
+	}
+}
@@ -0,0 +1,367 @@
+package bin
+
+import (
+	"bytes"
+	"errors"
+	"math/big"
+	"reflect"
+	"time"
+)
+
+// Format is a data format used by WriteVar and ReadVar.
+// Typed methods of Writer and Reader (WriteString, ReadVarInt, ...) always use the native format.
+type Format uint8
+
+const (
+	// FormatBin is native binary format of package
+	FormatBin Format = iota
+
+	// FormatMsgPack is MessagePack (https://msgpack.org).
+	// Structs are encoded as maps of field names, time.Time as timestamp extension (-1),
+	// big.Int as extension MsgPackExtBigInt with payload written by WriteBigInt.
+	// Values with own encoding (Encoder, BinaryMarshaler, BinWrite, ...) are written as bin
+	// containing their native encoding.
+	FormatMsgPack
+
+	// FormatCBOR is CBOR (RFC 8949) with core deterministic encoding: the shortest form of integers,
+	// lengths and floats, definite lengths and map keys sorted by their encoding.
+	// Structs are encoded as maps of field names, time.Time as epoch-based date/time (tag 1),
+	// big.Int as integer or bignum (tags 2, 3), Bytes as byte string.
+	// Values with own encoding are written as byte strings containing their native encoding.
+	FormatCBOR
+)
+
+var errIncompatibleType = errors.New("bin.Format-Error: incompatible type of value")
+
+// maxNestingDepth limits nesting of data items (arrays, maps, tags) read in self-describing formats
+const maxNestingDepth = 1000
+
+// enter increments nesting depth of data items; it sets errCorrupted if data is nested too deep.
+// Every call must be paired with leave.
+func (r *Reader) enter(errCorrupted error) error {
+	if r.depth++; r.depth > maxNestingDepth {
+		r.SetError(errCorrupted)
+	}
+	return r.err
+}
+
+func (r *Reader) leave() {
+	r.depth--
+}
+
+func (w *Writer) Format() Format {
+	return w.format
+}
+
+func (w *Writer) SetFormat(f Format) {
+	w.format = f
+}
+
+func (r *Reader) Format() Format {
+	return r.format
+}
+
+func (r *Reader) SetFormat(f Format) {
+	r.format = f
+}
+
+func (b *Buffer) Format() Format {
+	return b.Writer.format
+}
+
+// SetFormat sets format of both reader and writer of buffer
+func (b *Buffer) SetFormat(f Format) {
+	b.Writer.format = f
+	b.Reader.format = f
+}
+
+var (
+	typeTime   = reflect.TypeOf(time.Time{})
+	typeBigInt = reflect.TypeOf(big.Int{})
+)
+
+// isOpaque returns true if value of type is written in self-describing format as bytes of native encoding
+func isOpaque(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Complex64, reflect.Complex128:
+		return true
+	}
+	return t != typeTime && t != typeBigInt && !t.Implements(typeTextEncoder) && hasOwnEncoding(t)
+}
+
+// textEncoder is implemented by Bytes and its typed forms (they are bytes in all formats)
+type textEncoder interface {
+	TextEncoding() TextEncoding
+}
+
+var typeTextEncoder = reflect.TypeOf((*textEncoder)(nil)).Elem()
+
+// encodeOpaque returns native encoding of value
+func (w *Writer) encodeOpaque(v any) ([]byte, error) {
+	var buf bytes.Buffer
+	ww := Writer{wr: &buf, mode: w.mode}
+	err := ww.writeVar(v)
+	return append([]byte{}, buf.Bytes()...), err
+}
+
+// structEntries returns names and values of struct fields in self-describing formats.
+// Names are resolved as in native struct codec, so they are unique. Fields of nil embedded pointers are omitted.
+func (w *Writer) structEntries(rv reflect.Value) (keys, vals []any) {
+	info := getStructInfo(rv.Type())
+	if err := info.check(rv.Type(), w.mode); err != nil {
+		w.SetError(err)
+		return
+	}
+	for i := 0; i < len(info.fields); i++ {
+		f := info.fields[i]
+		if fv := rv.FieldByIndex(f.index); !f.ptr {
+			keys, vals = append(keys, f.key), append(vals, fv.Interface())
+		} else if fv.IsNil() {
+			i += f.n
+		}
+	}
+	return
+}
+
+// setDecoded sets value decoded by self-describing format (nil, bool, int64, uint64, float32, float64,
+// string, []byte, []any, map[string]any, map[any]any, time.Time, *big.Int) to dst
+func (r *Reader) setDecoded(dst reflect.Value, v any) error {
+	t := dst.Type()
+	if v == nil {
+		dst.Set(reflect.Zero(t))
+		return nil
+	}
+	switch t {
+	case typeBigInt:
+		if x := toBigInt(v); x != nil {
+			dst.Set(reflect.ValueOf(x).Elem())
+			return nil
+		}
+		return errIncompatibleType
+	case reflect.PointerTo(typeBigInt):
+		if x := toBigInt(v); x != nil {
+			dst.Set(reflect.ValueOf(x))
+			return nil
+		}
+		return errIncompatibleType
+	}
+	if t.Kind() == reflect.Ptr {
+		if dst.IsNil() {
+			dst.Set(reflect.New(t.Elem()))
+		}
+		return r.setDecoded(dst.Elem(), v)
+	}
+	if t.Kind() == reflect.Interface {
+		if !reflect.TypeOf(v).AssignableTo(t) {
+			return errIncompatibleType
+		}
+		dst.Set(reflect.ValueOf(v))
+		return nil
+	}
+	if t == typeRawValue {
+		ww := Writer{wr: new(bytes.Buffer), mode: r.mode, format: r.format}
+		if err := ww.writeVar(v); err != nil {
+			return err
+		}
+		dst.SetBytes(ww.wr.(*bytes.Buffer).Bytes())
+		return nil
+	}
+	if isOpaque(t) {
+		b, ok := v.([]byte)
+		if !ok {
+			return errIncompatibleType
+		}
+		rr := Reader{rd: bytes.NewReader(b), mode: r.mode}
+		return rr.readVar(dst.Addr().Interface())
+	}
+	if t == typeTime {
+		if x, ok := v.(time.Time); ok {
+			dst.Set(reflect.ValueOf(x))
+			return nil
+		}
+		return errIncompatibleType
+	}
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		switch x := v.(type) {
+		case int64:
+			if !dst.OverflowInt(x) {
+				dst.SetInt(x)
+				return nil
+			}
+		}
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		switch x := v.(type) {
+		case int64:
+			if x >= 0 && !dst.OverflowUint(uint64(x)) {
+				dst.SetUint(uint64(x))
+				return nil
+			}
+		case uint64:
+			if !dst.OverflowUint(x) {
+				dst.SetUint(x)
+				return nil
+			}
+		}
+
+	case reflect.Float32, reflect.Float64:
+		switch x := v.(type) {
+		case float32:
+			dst.SetFloat(float64(x))
+			return nil
+		case float64:
+			dst.SetFloat(x)
+			return nil
+		case int64:
+			dst.SetFloat(float64(x))
+			return nil
+		case uint64:
+			dst.SetFloat(float64(x))
+			return nil
+		}
+
+	case reflect.Bool:
+		if x, ok := v.(bool); ok {
+			dst.SetBool(x)
+			return nil
+		}
+
+	case reflect.String:
+		switch x := v.(type) {
+		case string:
+			dst.SetString(x)
+			return nil
+		case []byte:
+			dst.SetString(string(x))
+			return nil
+		}
+
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			var b []byte
+			switch x := v.(type) {
+			case []byte:
+				b = x
+			case string:
+				b = []byte(x)
+			}
+			if b != nil && t.Kind() == reflect.Slice {
+				dst.SetBytes(append([]byte{}, b...))
+				return nil
+			} else if b != nil && len(b) == t.Len() {
+				reflect.Copy(dst, reflect.ValueOf(b))
+				return nil
+			}
+		}
+		arr, ok := v.([]any)
+		if !ok {
+			break
+		}
+		if t.Kind() == reflect.Slice {
+			dst.Set(reflect.MakeSlice(t, len(arr), len(arr)))
+		} else if len(arr) != t.Len() {
+			break
+		}
+		for i, x := range arr {
+			if err := r.setDecoded(dst.Index(i), x); err != nil {
+				return err
+			}
+		}
+		return nil
+
+	case reflect.Map:
+		mp := reflect.MakeMap(t)
+		key := reflect.New(t.Key()).Elem()
+		val := reflect.New(t.Elem()).Elem()
+		set := func(k, v any) error {
+			if err := r.setDecoded(key, k); err != nil {
+				return err
+			}
+			if err := r.setDecoded(val, v); err != nil {
+				return err
+			}
+			mp.SetMapIndex(key, val)
+			return nil
+		}
+		switch x := v.(type) {
+		case map[string]any:
+			for k, v := range x {
+				if err := set(k, v); err != nil {
+					return err
+				}
+			}
+		case map[any]any:
+			for k, v := range x {
+				if err := set(k, v); err != nil {
+					return err
+				}
+			}
+		default:
+			return errIncompatibleType
+		}
+		dst.Set(mp)
+		return nil
+
+	case reflect.Struct:
+		var get func(string) (any, bool)
+		switch x := v.(type) {
+		case map[string]any:
+			get = func(k string) (v any, ok bool) { v, ok = x[k]; return }
+		case map[any]any:
+			get = func(k string) (v any, ok bool) { v, ok = x[k]; return }
+		default:
+			return errIncompatibleType
+		}
+		info := getStructInfo(t)
+		if err := info.check(t, r.mode); err != nil {
+			return err
+		}
+		for _, f := range info.fields {
+			if fv, ok := get(f.key); ok && !f.ptr {
+				if err := r.setDecoded(fieldByIndex(dst, f.index), fv); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}
+	return errIncompatibleType
+}
+
+func toBigInt(v any) *big.Int {
+	switch x := v.(type) {
+	case *big.Int:
+		return x
+	case int64:
+		return big.NewInt(x)
+	case uint64:
+		return new(big.Int).SetUint64(x)
+	}
+	return nil
+}
+
+// newDecodedMap returns map[string]any if all keys are strings, otherwise map[any]any
+func newDecodedMap(keys, vals []any) (any, error) {
+	strKeys := true
+	for _, k := range keys {
+		if _, ok := k.(string); !ok {
+			strKeys = false
+		}
+		if k != nil && !reflect.TypeOf(k).Comparable() {
+			return nil, errIncompatibleType
+		}
+	}
+	if strKeys {
+		m := make(map[string]any, len(keys))
+		for i, k := range keys {
+			m[k.(string)] = vals[i]
+		}
+		return m, nil
+	}
+	m := make(map[any]any, len(keys))
+	for i, k := range keys {
+		m[k] = vals[i]
+	}
+	return m, nil
+}
@@ -1,42 +1,188 @@
 package bin
 
-import "crypto/sha256"
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"hash"
+)
+
+// Hasher calculates a hash of values encoded by Writer.
+// Values are written to the underlying hash incrementally, so large collections
+// can be hashed without encoding them into memory first.
+type Hasher struct {
+	hash.Hash
+	w *Writer
+}
+
+// NewHasher returns Hasher based on the given hash algorithm (sha256 by default).
+func NewHasher(h hash.Hash) *Hasher {
+	if h == nil {
+		h = sha256.New()
+	}
+	return &Hasher{h, NewWriter(h)}
+}
+
+// Add writes values to the hash using the same encoding as Writer.WriteVar.
+func (h *Hasher) Add(values ...any) error {
+	return h.w.WriteVar(values...)
+}
+
+// Error returns the first error occurred while adding values.
+func (h *Hasher) Error() error {
+	return h.w.Error()
+}
+
+// SetFormat sets format of values added to the hash.
+// FormatCBOR gives hashes of canonical (deterministic) CBOR encoding of values.
+func (h *Hasher) SetFormat(f Format) {
+	h.w.SetFormat(f)
+}
+
+func (h *Hasher) Reset() {
+	h.Hash.Reset()
+	h.w = &Writer{wr: h.Hash, format: h.w.format}
+}
+
+func (h *Hasher) Sum32() uint32 {
+	return BytesToUint32(h.sum(4))
+}
+
+func (h *Hasher) Sum64() uint64 {
+	return BytesToUint64(h.sum(8))
+}
+
+func (h *Hasher) Sum128() []byte {
+	return h.sum(16)
+}
+
+func (h *Hasher) Sum160() []byte {
+	return h.sum(20)
+}
+
+func (h *Hasher) Sum256() []byte {
+	return h.sum(32)
+}
+
+func (h *Hasher) sum(n int) []byte {
+	s := h.Sum(nil)
+	if len(s) > n {
+		s = s[:n]
+	}
+	return s
+}
 
 func Hash32(values ...any) uint32 {
-	h256 := Hash256(values...)
-	return BytesToUint32(h256[:4])
+	return newHasher(values).Sum32()
 }
 
 func Hash64(values ...any) uint64 {
-	h256 := Hash256(values...)
-	return BytesToUint64(h256)
+	return newHasher(values).Sum64()
 }
 
 func Hash128(values ...any) []byte {
-	h256 := Hash256(values...)
-	return h256[:16]
+	return newHasher(values).Sum128()
 }
 
 func Hash160(values ...any) []byte {
-	h256 := Hash256(values...)
-	return h256[:20]
+	return newHasher(values).Sum160()
 }
 
 func Hash256(values ...any) []byte {
-	hash := sha256.New()
-	w := NewWriter(hash)
-	for _, val := range values {
-		w.WriteVar(val)
-	}
-	return hash.Sum(nil)
+	return newHasher(values).Sum256()
 }
 
-// FastHash64 is fast non-cryptographic hash function
-func FastHash64(values ...any) uint64 {
-	data := Encode(values...)
-	h := uint64(14695981039346656037)
-	for _, c := range data {
-		h = (h * 1099511628211) ^ (uint64(c) * 1073676287)
-	}
+// HashCBOR256 returns sha256 hash of canonical CBOR encoding of values
+func HashCBOR256(values ...any) []byte {
+	h := NewHasher(nil)
+	h.SetFormat(FormatCBOR)
+	h.Add(values...)
+	return h.Sum256()
+}
+
+func newHasher(values []any) *Hasher {
+	h := NewHasher(nil)
+	h.Add(values...)
+	return h
+}
+
+//----------- domain separated hashes --------------
+
+// HashWithDomain returns 256-bit hash of values separated by domain (context string).
+// Hashes of the same values in different domains are independent.
+func HashWithDomain(domain string, values ...any) []byte {
+	return newDomainHasher(domain, values).Sum256()
+}
+
+func Hash32WithDomain(domain string, values ...any) uint32 {
+	return newDomainHasher(domain, values).Sum32()
+}
+
+func Hash64WithDomain(domain string, values ...any) uint64 {
+	return newDomainHasher(domain, values).Sum64()
+}
+
+func Hash128WithDomain(domain string, values ...any) []byte {
+	return newDomainHasher(domain, values).Sum128()
+}
+
+func Hash160WithDomain(domain string, values ...any) []byte {
+	return newDomainHasher(domain, values).Sum160()
+}
+
+// newDomainHasher starts the hash with length-prefixed domain tag,
+// so different domains never produce the same input stream.
+func newDomainHasher(domain string, values []any) *Hasher {
+	h := NewHasher(nil)
+	h.w.WriteString("bin.domain")
+	h.w.WriteString(domain)
+	h.Add(values...)
 	return h
 }
+
+//----------- keyed hashes (HMAC-SHA256) --------------
+
+func HMAC32(key []byte, values ...any) uint32 {
+	return newHMACHasher(key, values).Sum32()
+}
+
+func HMAC64(key []byte, values ...any) uint64 {
+	return newHMACHasher(key, values).Sum64()
+}
+
+func HMAC128(key []byte, values ...any) []byte {
+	return newHMACHasher(key, values).Sum128()
+}
+
+func HMAC160(key []byte, values ...any) []byte {
+	return newHMACHasher(key, values).Sum160()
+}
+
+func HMAC256(key []byte, values ...any) []byte {
+	return newHMACHasher(key, values).Sum256()
+}
+
+// VerifyHMAC256 checks mac of values in constant time.
+func VerifyHMAC256(mac, key []byte, values ...any) bool {
+	return hmac.Equal(mac, HMAC256(key, values...))
+}
+
+func newHMACHasher(key []byte, values []any) *Hasher {
+	h := NewHasher(hmac.New(sha256.New, key))
+	h.Add(values...)
+	return h
+}
+
+// FastHash64 is fast non-cryptographic hash function (xxHash64 of encoded values)
+func FastHash64(values ...any) uint64 {
+	return FastHash64Seed(0, values...)
+}
+
+// FastHash64Seed is seeded version of FastHash64.
+// Values are fed to the hash directly from Writer without intermediate buffer.
+func FastHash64Seed(seed uint64, values ...any) uint64 {
+	var d xxHash64
+	d.reset(seed)
+	w := Writer{wr: &d}
+	w.WriteVar(values...)
+	return d.Sum64()
+}
@@ -0,0 +1,139 @@
+package bin
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/sha3"
+	"crypto/sha512"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHash256(t *testing.T) {
+	data := Encode(uint64(123), "abc", []int{1, 2, 3})
+	h := sha256.Sum256(data)
+
+	hash := Hash256(uint64(123), "abc", []int{1, 2, 3})
+
+	assert.Equal(t, h[:], hash)
+	assert.Equal(t, h[:20], Hash160(uint64(123), "abc", []int{1, 2, 3}))
+	assert.Equal(t, BytesToUint64(h[:8]), Hash64(uint64(123), "abc", []int{1, 2, 3}))
+}
+
+func TestHasher_Add(t *testing.T) {
+	h := NewHasher(nil)
+	for i := 0; i < 1000; i++ {
+		h.Add(i, "item")
+	}
+
+	var vv []any
+	for i := 0; i < 1000; i++ {
+		vv = append(vv, i, "item")
+	}
+
+	assert.NoError(t, h.Error())
+	assert.Equal(t, Hash256(vv...), h.Sum256())
+	assert.Equal(t, Hash32(vv...), h.Sum32())
+}
+
+func TestHasher_SHA512(t *testing.T) {
+	h := NewHasher(sha512.New())
+	h.Add("abc", 123)
+
+	sum := sha512.Sum512(Encode("abc", 123))
+
+	assert.Equal(t, sum[:], h.Sum(nil))
+	assert.Equal(t, sum[:32], h.Sum256())
+	assert.Equal(t, BytesToUint64(sum[:8]), h.Sum64())
+}
+
+func TestHasher_SHA3(t *testing.T) {
+	h := NewHasher(sha3.New256())
+	h.Add("abc", 123)
+
+	sum := sha3.Sum256(Encode("abc", 123))
+
+	assert.Equal(t, sum[:], h.Sum256())
+}
+
+func TestHasher_Reset(t *testing.T) {
+	h := NewHasher(nil)
+	h.Add("abc")
+	h.Reset()
+	h.Add(123)
+
+	assert.Equal(t, Hash256(123), h.Sum256())
+}
+
+func TestXXHash64(t *testing.T) {
+	for _, c := range []struct {
+		data string
+		seed uint64
+		hash uint64
+	}{
+		{"", 0, 0xef46db3751d8e999},
+		{"a", 0, 0xd24ec4f1a98c6e5b},
+		{"abc", 0, 0x44bc2cf5ad770999},
+		{"Nobody inspects the spammish repetition", 0, 0xfbcea83c8a378bf1},
+		{"The quick brown fox jumps over the lazy dog", 0, 0x0b242d361fda71bc},
+	} {
+		var d xxHash64
+		d.reset(c.seed)
+		for i := 0; i < len(c.data); i += 7 { // write by chunks
+			d.Write([]byte(c.data[i:min(i+7, len(c.data))]))
+		}
+		assert.Equal(t, c.hash, d.Sum64(), c.data)
+	}
+}
+
+func TestFastHash64(t *testing.T) {
+	var d xxHash64
+	d.reset(0)
+	d.Write(Encode(uint64(123), "abc", []int{1, 2, 3}))
+
+	h := FastHash64(uint64(123), "abc", []int{1, 2, 3})
+
+	assert.Equal(t, d.Sum64(), h)
+	assert.NotEqual(t, h, FastHash64(uint64(124), "abc", []int{1, 2, 3}))
+}
+
+func TestFastHash64Seed(t *testing.T) {
+	h0 := FastHash64Seed(0, "abc", 123)
+	h1 := FastHash64Seed(1, "abc", 123)
+
+	assert.Equal(t, FastHash64("abc", 123), h0)
+	assert.NotEqual(t, h0, h1)
+	assert.Equal(t, h1, FastHash64Seed(1, "abc", 123))
+}
+
+func TestHashWithDomain(t *testing.T) {
+	h1 := HashWithDomain("cache", "abc", 123)
+	h2 := HashWithDomain("token", "abc", 123)
+
+	assert.Equal(t, 32, len(h1))
+	assert.NotEqual(t, h1, h2)
+	assert.NotEqual(t, Hash256("abc", 123), h1)
+	assert.Equal(t, h1, HashWithDomain("cache", "abc", 123))
+	assert.Equal(t, h1[:20], Hash160WithDomain("cache", "abc", 123))
+	assert.Equal(t, BytesToUint64(h1), Hash64WithDomain("cache", "abc", 123))
+	assert.NotEqual(t, Hash64WithDomain("cache", "abc", 123), Hash64WithDomain("token", "abc", 123))
+	assert.NotEqual(t, HashWithDomain("a", "abc"), HashWithDomain("a\x00", "abc"))
+	assert.NotEqual(t, HashWithDomain("", "abc"), HashWithDomain("\x00", "abc"))
+	assert.NotEqual(t, HMAC256([]byte("bin.domain:cache"), "abc", 123), h1)
+}
+
+func TestHMAC256(t *testing.T) {
+	key := []byte("secret")
+	mac := hmac.New(sha256.New, key)
+	mac.Write(Encode("user", 123))
+
+	h := HMAC256(key, "user", 123)
+
+	assert.Equal(t, mac.Sum(nil), h)
+	assert.Equal(t, h[:16], HMAC128(key, "user", 123))
+	assert.Equal(t, BytesToUint32(h), HMAC32(key, "user", 123))
+	assert.True(t, VerifyHMAC256(h, key, "user", 123))
+	assert.False(t, VerifyHMAC256(h, key, "user", 124))
+	assert.False(t, VerifyHMAC256(h, []byte("secret2"), "user", 123))
+}
@@ -0,0 +1,111 @@
+package bin
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+)
+
+// H128, H160, H256 are fixed-size hash values.
+// They are comparable (can be used as map keys) and are encoded by Writer as raw bytes.
+type (
+	H128 [16]byte
+	H160 [20]byte
+	H256 [32]byte
+)
+
+var errInvalidHashLength = errors.New("bin.Hash-Error: invalid length of hash")
+
+func HashH128(values ...any) (h H128) {
+	copy(h[:], Hash128(values...))
+	return
+}
+
+func HashH160(values ...any) (h H160) {
+	copy(h[:], Hash160(values...))
+	return
+}
+
+func HashH256(values ...any) (h H256) {
+	copy(h[:], Hash256(values...))
+	return
+}
+
+func ParseH128(s string) (h H128, err error) {
+	err = parseHash(h[:], s)
+	return
+}
+
+func ParseH160(s string) (h H160, err error) {
+	err = parseHash(h[:], s)
+	return
+}
+
+func ParseH256(s string) (h H256, err error) {
+	err = parseHash(h[:], s)
+	return
+}
+
+// parseHash decodes hash from hex text with optional 0x prefix (text and JSON forms use the same rule)
+func parseHash(h []byte, s string) error {
+	b, err := decodeHex(s)
+	if err != nil {
+		return err
+	}
+	if len(b) != len(h) {
+		return errInvalidHashLength
+	}
+	copy(h, b)
+	return nil
+}
+
+func unmarshalHashJSON(h []byte, data []byte) error {
+	if string(data) == "null" {
+		clear(h)
+		return nil
+	}
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	return parseHash(h, s)
+}
+
+//----------- H128 --------------
+
+func (h H128) String() string                   { return Bytes(h[:]).String() }
+func (h H128) Bytes() []byte                    { return h[:] }
+func (h H128) IsZero() bool                     { return h == H128{} }
+func (h H128) Compare(b H128) int               { return bytes.Compare(h[:], b[:]) }
+func (h H128) MarshalText() ([]byte, error)     { return Bytes(h[:]).MarshalText() }
+func (h H128) MarshalJSON() ([]byte, error)     { return Bytes(h[:]).MarshalJSON() }
+func (h *H128) UnmarshalText(data []byte) error { return parseHash(h[:], string(data)) }
+func (h *H128) UnmarshalJSON(data []byte) error { return unmarshalHashJSON(h[:], data) }
+func (h H128) BinWrite(w *Writer)               { w.Write(h[:]) }
+func (h *H128) BinRead(r *Reader)               { r.Read(h[:]) }
+
+//----------- H160 --------------
+
+func (h H160) String() string                   { return Bytes(h[:]).String() }
+func (h H160) Bytes() []byte                    { return h[:] }
+func (h H160) IsZero() bool                     { return h == H160{} }
+func (h H160) Compare(b H160) int               { return bytes.Compare(h[:], b[:]) }
+func (h H160) MarshalText() ([]byte, error)     { return Bytes(h[:]).MarshalText() }
+func (h H160) MarshalJSON() ([]byte, error)     { return Bytes(h[:]).MarshalJSON() }
+func (h *H160) UnmarshalText(data []byte) error { return parseHash(h[:], string(data)) }
+func (h *H160) UnmarshalJSON(data []byte) error { return unmarshalHashJSON(h[:], data) }
+func (h H160) BinWrite(w *Writer)               { w.Write(h[:]) }
+func (h *H160) BinRead(r *Reader)               { r.Read(h[:]) }
+
+//----------- H256 --------------
+
+func (h H256) String() string                   { return Bytes(h[:]).String() }
+func (h H256) Bytes() []byte                    { return h[:] }
+func (h H256) IsZero() bool                     { return h == H256{} }
+func (h H256) Compare(b H256) int               { return bytes.Compare(h[:], b[:]) }
+func (h H256) MarshalText() ([]byte, error)     { return Bytes(h[:]).MarshalText() }
+func (h H256) MarshalJSON() ([]byte, error)     { return Bytes(h[:]).MarshalJSON() }
+func (h *H256) UnmarshalText(data []byte) error { return parseHash(h[:], string(data)) }
+func (h *H256) UnmarshalJSON(data []byte) error { return unmarshalHashJSON(h[:], data) }
+func (h H256) BinWrite(w *Writer)               { w.Write(h[:]) }
+func (h *H256) BinRead(r *Reader)               { r.Read(h[:]) }
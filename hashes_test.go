@@ -0,0 +1,114 @@
+package bin
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHashH256(t *testing.T) {
+	h := HashH256("abc", 123)
+
+	assert.Equal(t, Hash256("abc", 123), h.Bytes())
+	assert.Equal(t, Bytes(Hash256("abc", 123)).String(), h.String())
+	assert.Equal(t, Hash160("abc", 123), HashH160("abc", 123).Bytes())
+	assert.Equal(t, Hash128("abc", 123), HashH128("abc", 123).Bytes())
+}
+
+func TestH256_MapKey(t *testing.T) {
+	m := map[H256]int{}
+	m[HashH256("a")] = 1
+	m[HashH256("b")] = 2
+
+	assert.Equal(t, 1, m[HashH256("a")])
+	assert.Equal(t, 2, m[HashH256("b")])
+}
+
+func TestH256_Compare(t *testing.T) {
+	var zero H256
+	h1 := H256{1}
+	h2 := H256{2}
+
+	assert.True(t, zero.IsZero())
+	assert.False(t, h1.IsZero())
+	assert.Equal(t, -1, h1.Compare(h2))
+	assert.Equal(t, 1, h2.Compare(h1))
+	assert.Equal(t, 0, h1.Compare(H256{1}))
+}
+
+func TestParseH256(t *testing.T) {
+	h := HashH256("abc")
+
+	h1, err1 := ParseH256(h.String())
+	_, err2 := ParseH256("0102")
+	_, err3 := ParseH256("xyz!")
+
+	assert.NoError(t, err1)
+	assert.Equal(t, h, h1)
+	assert.Error(t, err2)
+	assert.Error(t, err3)
+}
+
+func TestParseH256_TextEncodings(t *testing.T) {
+	for _, enc := range []TextEncoding{EncodingHex, Encoding0xHex} {
+		for i := 0; i < 100; i++ {
+			h := HashH256(i)
+			s := Bytes(h[:]).EncodeToString(enc)
+
+			res, err := ParseH256(s)
+			var res2 H256
+			err2 := json.Unmarshal([]byte(`"`+s+`"`), &res2)
+
+			assert.NoError(t, err, s)
+			assert.NoError(t, err2, s)
+			assert.Equal(t, h, res, s)
+			assert.Equal(t, h, res2, s)
+		}
+	}
+	for _, enc := range []TextEncoding{EncodingBase64, EncodingBase64URL, EncodingBase58} {
+		h := HashH256("abc")
+		s := Bytes(h[:]).EncodeToString(enc)
+
+		_, err := ParseH256(s)
+		var res2 H256
+		err2 := json.Unmarshal([]byte(`"`+s+`"`), &res2)
+
+		assert.Error(t, err, s)
+		assert.Error(t, err2, s)
+	}
+}
+
+func TestH160_JSON(t *testing.T) {
+	type Obj struct {
+		Hash H160
+		Refs map[H160]int
+	}
+	h := HashH160("abc")
+	obj := Obj{h, map[H160]int{h: 1}}
+
+	data, err := json.Marshal(obj)
+	var res Obj
+	err2 := json.Unmarshal(data, &res)
+
+	assert.NoError(t, err)
+	assert.NoError(t, err2)
+	assert.Equal(t, `{"Hash":"`+h.String()+`","Refs":{"`+h.String()+`":1}}`, string(data))
+	assert.Equal(t, obj, res)
+}
+
+func TestH256_Encode(t *testing.T) {
+	h := HashH256("abc")
+	hh := []H256{HashH256(1), HashH256(2)}
+
+	data := Encode(h, hh)
+	var res H256
+	var res2 []H256
+	err := Decode(data, &res, &res2)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 32+1+64, len(data))
+	assert.Equal(t, h[:], data[:32])
+	assert.Equal(t, h, res)
+	assert.Equal(t, hh, res2)
+}
@@ -0,0 +1,152 @@
+package bin
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+)
+
+// Merkle tree hashes are domain-separated (as in RFC 6962):
+//
+//	leaf = sha256(0x00 || encode(value))
+//	node = sha256(0x01 || left || right)
+//
+// A node without a right sibling is promoted to the upper level unchanged.
+const (
+	merkleLeafPrefix = 0x00
+	merkleNodePrefix = 0x01
+)
+
+var errInvalidMerkleProof = errors.New("bin.MerkleProof-Error: invalid proof")
+
+type MerkleTree struct {
+	levels [][][]byte // levels[0] are leaf hashes, last level is root
+}
+
+type MerkleProof struct {
+	Index  int      // index of leaf
+	Size   int      // number of leaves in tree
+	Hashes [][]byte // sibling hashes from leaf to root
+}
+
+// MerkleRoot returns root hash of merkle tree built from values.
+func MerkleRoot(values ...any) []byte {
+	return NewMerkleTree(values...).Root()
+}
+
+func NewMerkleTree(values ...any) *MerkleTree {
+	leaves := make([][]byte, len(values))
+	for i, v := range values {
+		leaves[i] = MerkleLeafHash(v)
+	}
+	return NewMerkleTreeFromHashes(leaves)
+}
+
+// NewMerkleTreeFromHashes builds merkle tree from leaf hashes (see MerkleLeafHash).
+func NewMerkleTreeFromHashes(leaves [][]byte) *MerkleTree {
+	t := &MerkleTree{levels: [][][]byte{leaves}}
+	for level := leaves; len(level) > 1; {
+		next := make([][]byte, (len(level)+1)/2)
+		for i := range next {
+			if j := 2 * i; j+1 < len(level) {
+				next[i] = merkleNodeHash(level[j], level[j+1])
+			} else {
+				next[i] = level[j]
+			}
+		}
+		t.levels = append(t.levels, next)
+		level = next
+	}
+	return t
+}
+
+// MerkleLeafHash returns hash of leaf value.
+func MerkleLeafHash(value any) []byte {
+	h := NewHasher(nil)
+	h.Write([]byte{merkleLeafPrefix})
+	h.Add(value)
+	return h.Sum(nil)
+}
+
+func merkleNodeHash(left, right []byte) []byte {
+	h := sha256.New()
+	h.Write([]byte{merkleNodePrefix})
+	h.Write(left)
+	h.Write(right)
+	return h.Sum(nil)
+}
+
+// Len returns count of leaves.
+func (t *MerkleTree) Len() int {
+	return len(t.levels[0])
+}
+
+// Root returns root hash of tree. Root of empty tree is sha256 of empty string.
+func (t *MerkleTree) Root() []byte {
+	if t.Len() == 0 {
+		h := sha256.Sum256(nil)
+		return h[:]
+	}
+	return t.levels[len(t.levels)-1][0]
+}
+
+// Proof returns inclusion proof of i-th leaf or nil if index is out of range.
+func (t *MerkleTree) Proof(i int) *MerkleProof {
+	if i < 0 || i >= t.Len() {
+		return nil
+	}
+	p := &MerkleProof{Index: i, Size: t.Len()}
+	for _, level := range t.levels[:len(t.levels)-1] {
+		if sibling := i ^ 1; sibling < len(level) {
+			p.Hashes = append(p.Hashes, level[sibling])
+		}
+		i /= 2
+	}
+	return p
+}
+
+// VerifyProof checks that leaf value is included into merkle tree with given root.
+func VerifyProof(root []byte, leaf any, proof *MerkleProof) bool {
+	if proof == nil {
+		return false
+	}
+	h, err := proof.Root(MerkleLeafHash(leaf))
+	return err == nil && bytes.Equal(h, root)
+}
+
+// Root calculates root hash of tree by leaf hash and proof.
+func (p *MerkleProof) Root(leafHash []byte) ([]byte, error) {
+	if p.Index < 0 || p.Index >= p.Size {
+		return nil, errInvalidMerkleProof
+	}
+	h, hashes := leafHash, p.Hashes
+	for i, n := p.Index, p.Size; n > 1; i, n = i/2, (n+1)/2 {
+		if i%2 == 1 || i+1 < n { // node has sibling
+			if len(hashes) == 0 {
+				return nil, errInvalidMerkleProof
+			}
+			if i%2 == 1 {
+				h = merkleNodeHash(hashes[0], h)
+			} else {
+				h = merkleNodeHash(h, hashes[0])
+			}
+			hashes = hashes[1:]
+		}
+	}
+	if len(hashes) != 0 {
+		return nil, errInvalidMerkleProof
+	}
+	return h, nil
+}
+
+func (p *MerkleProof) BinWrite(w *Writer) {
+	w.WriteVarInt(p.Index)
+	w.WriteVarInt(p.Size)
+	w.WriteSliceBytes(p.Hashes)
+}
+
+func (p *MerkleProof) BinRead(r *Reader) {
+	p.Index, _ = r.ReadVarInt()
+	p.Size, _ = r.ReadVarInt()
+	p.Hashes, _ = r.ReadSliceBytes()
+}
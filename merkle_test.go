@@ -0,0 +1,81 @@
+package bin
+
+import (
+	"crypto/sha256"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMerkleRoot(t *testing.T) {
+	// root by RFC 6962 definition
+	var mth func(vv []any) []byte
+	mth = func(vv []any) []byte {
+		if len(vv) == 1 {
+			return MerkleLeafHash(vv[0])
+		}
+		k := 1
+		for k*2 < len(vv) {
+			k *= 2
+		}
+		return merkleNodeHash(mth(vv[:k]), mth(vv[k:]))
+	}
+
+	var values []any
+	for n := 1; n <= 17; n++ {
+		values = append(values, n, "abc")
+
+		assert.Equal(t, mth(values), MerkleRoot(values...))
+	}
+}
+
+func TestMerkleRoot_Empty(t *testing.T) {
+	h := sha256.Sum256(nil)
+
+	assert.Equal(t, h[:], MerkleRoot())
+}
+
+func TestMerkleRoot_LeafDomain(t *testing.T) {
+	assert.NotEqual(t, Hash256("abc"), MerkleRoot("abc"))
+}
+
+func TestMerkleTree_Proof(t *testing.T) {
+	for n := 1; n <= 17; n++ {
+		var values []any
+		for i := 0; i < n; i++ {
+			values = append(values, []int{i, i * i})
+		}
+		tree := NewMerkleTree(values...)
+		root := tree.Root()
+
+		for i, v := range values {
+			proof := tree.Proof(i)
+
+			assert.True(t, VerifyProof(root, v, proof))
+			assert.False(t, VerifyProof(root, []int{i, -1}, proof))
+		}
+		assert.Nil(t, tree.Proof(n))
+	}
+}
+
+func TestMerkleProof_Encode(t *testing.T) {
+	tree := NewMerkleTree("a", "b", "c", "d", "e")
+	proof := tree.Proof(2)
+
+	data := Encode(proof)
+
+	var p MerkleProof
+	err := Decode(data, &p)
+	assert.NoError(t, err)
+	assert.Equal(t, proof, &p)
+	assert.True(t, VerifyProof(tree.Root(), "c", &p))
+}
+
+func TestMerkleProof_Invalid(t *testing.T) {
+	tree := NewMerkleTree("a", "b", "c", "d", "e")
+	proof := tree.Proof(2)
+	proof.Size = 4
+
+	assert.False(t, VerifyProof(tree.Root(), "c", proof))
+	assert.False(t, VerifyProof(tree.Root(), "c", nil))
+}
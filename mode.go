@@ -0,0 +1,60 @@
+package bin
+
+// Mode is a set of optional encoding features of Writer and Reader.
+// Data must be read in the same mode as it was written.
+type Mode uint32
+
+const (
+	// ModeStructs encodes plain structs natively (field by field in order of declaration) instead of gob.
+	// Embedded structs and pointers to structs are flattened as in encoding/json (fields hidden by
+	// fields with the same name are not encoded), unexported fields are skipped, fields with tag `bin:"-"` are ignored.
+	// Pointers to plain structs (including embedded ones) are written as presence byte and struct.
+	ModeStructs Mode = 1 << 0
+
+	// ModeStrictStructs is ModeStructs that fails on unexported fields instead of skipping them.
+	ModeStrictStructs Mode = 1<<1 | ModeStructs
+
+	// ModeNilEmpty preserves distinction between nil and empty slices, maps and byte strings.
+	// Nil is written as length -1, so encoding of non-nil values is not changed.
+	ModeNilEmpty Mode = 1 << 2
+
+	// ModeStringTable writes each distinct string once. The first occurrence is written inline
+	// and added to the string table, later occurrences are written as index in the table.
+	// The table lives until ResetStringTable is called, so it can be scoped per frame or per stream.
+	ModeStringTable Mode = 1 << 3
+
+	// ModeLEB128 writes varints as standard LEB128 (encoding/binary, protobuf):
+	// signed integers as zigzag varints, unsigned integers and length prefixes as unsigned varints.
+	// Fixed-width values and big integers are not affected.
+	ModeLEB128 Mode = 1 << 4
+
+	// ModeTimeFull writes time.Time values by WriteTimeFull (seconds and nanoseconds with location)
+	// instead of WriteTime, so zero time, times out of range 1678-2262 and zones round-trip.
+	ModeTimeFull Mode = 1 << 5
+)
+
+func (w *Writer) Mode() Mode {
+	return w.mode
+}
+
+func (w *Writer) SetMode(m Mode) {
+	w.mode = m
+}
+
+func (r *Reader) Mode() Mode {
+	return r.mode
+}
+
+func (r *Reader) SetMode(m Mode) {
+	r.mode = m
+}
+
+func (b *Buffer) Mode() Mode {
+	return b.Writer.mode
+}
+
+// SetMode sets mode of both reader and writer of buffer
+func (b *Buffer) SetMode(m Mode) {
+	b.Writer.mode = m
+	b.Reader.mode = m
+}
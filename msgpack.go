@@ -0,0 +1,481 @@
+package bin
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"math/bits"
+	"reflect"
+	"time"
+)
+
+// MsgPackExtBigInt is MessagePack extension type of big.Int
+const MsgPackExtBigInt int8 = 1
+
+const msgpackExtTime int8 = -1
+
+var (
+	errMsgPackCorrupted = errors.New("bin.MsgPack-Error: invalid data")
+	errMsgPackExt       = errors.New("bin.MsgPack-Error: unsupported extension type")
+)
+
+//----------- MessagePack writer --------------
+
+func (w *Writer) writeMsgPack(val any) error {
+	switch v := val.(type) {
+	case nil:
+		w.WriteByte(0xc0)
+	case bool:
+		if v {
+			w.WriteByte(0xc3)
+		} else {
+			w.WriteByte(0xc2)
+		}
+
+	case int:
+		w.msgpackInt(int64(v))
+	case int8:
+		w.msgpackInt(int64(v))
+	case int16:
+		w.msgpackInt(int64(v))
+	case int32:
+		w.msgpackInt(int64(v))
+	case int64:
+		w.msgpackInt(v)
+	case uint:
+		w.msgpackUint(uint64(v))
+	case uint8:
+		w.msgpackUint(uint64(v))
+	case uint16:
+		w.msgpackUint(uint64(v))
+	case uint32:
+		w.msgpackUint(uint64(v))
+	case uint64:
+		w.msgpackUint(v)
+	case float32:
+		w.WriteByte(0xca)
+		w.WriteFloat32(v)
+	case float64:
+		w.WriteByte(0xcb)
+		w.WriteFloat64(v)
+
+	case string:
+		w.msgpackHead(0xa0, 31, 0xd9, 0xda, 0xdb, len(v))
+		w.Write([]byte(v))
+	case []byte:
+		w.msgpackBytes(v)
+	case Bytes:
+		w.msgpackBytes(v)
+
+	case time.Time:
+		w.msgpackTime(v)
+	case *time.Time:
+		if v == nil {
+			w.WriteByte(0xc0)
+		} else {
+			w.msgpackTime(*v)
+		}
+	case *big.Int:
+		if v == nil {
+			w.WriteByte(0xc0)
+		} else {
+			w.msgpackBigInt(v)
+		}
+	case big.Int:
+		w.msgpackBigInt(&v)
+
+	case RawValue:
+		w.writeRawValue(v)
+
+	case error:
+		w.writeMsgPack(v.Error())
+
+	default:
+		rv := reflect.ValueOf(v)
+		if rv.Kind() == reflect.Ptr && rv.IsNil() {
+			w.WriteByte(0xc0)
+			break
+		}
+		if isOpaque(rv.Type()) {
+			if b, err := w.encodeOpaque(v); err != nil {
+				w.SetError(err)
+			} else {
+				w.msgpackBytes(b)
+			}
+			break
+		}
+		switch rv.Kind() {
+		case reflect.Ptr:
+			w.writeMsgPack(rv.Elem().Interface())
+
+		case reflect.Bool:
+			w.writeMsgPack(rv.Bool())
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			w.msgpackInt(rv.Int())
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			w.msgpackUint(rv.Uint())
+		case reflect.Float32:
+			w.writeMsgPack(float32(rv.Float()))
+		case reflect.Float64:
+			w.writeMsgPack(rv.Float())
+		case reflect.String:
+			w.writeMsgPack(rv.String())
+
+		case reflect.Slice, reflect.Array:
+			if rv.Type().Elem().Kind() == reflect.Uint8 && !(rv.Kind() == reflect.Slice && rv.IsNil()) {
+				b := make([]byte, rv.Len())
+				reflect.Copy(reflect.ValueOf(b), rv)
+				w.msgpackBytes(b)
+				break
+			}
+			if rv.Kind() == reflect.Slice && rv.IsNil() {
+				w.WriteByte(0xc0)
+				break
+			}
+			n := rv.Len()
+			w.msgpackHead(0x90, 15, 0, 0xdc, 0xdd, n)
+			for i := 0; i < n && w.err == nil; i++ {
+				w.writeMsgPack(rv.Index(i).Interface())
+			}
+
+		case reflect.Map:
+			if rv.IsNil() {
+				w.WriteByte(0xc0)
+				break
+			}
+			w.msgpackHead(0x80, 15, 0, 0xde, 0xdf, rv.Len())
+			for it := rv.MapRange(); it.Next() && w.err == nil; {
+				w.writeMsgPack(it.Key().Interface())
+				w.writeMsgPack(it.Value().Interface())
+			}
+
+		case reflect.Struct:
+			keys, vals := w.structEntries(rv)
+			if w.err != nil {
+				break
+			}
+			w.msgpackHead(0x80, 15, 0, 0xde, 0xdf, len(keys))
+			for i := range keys {
+				if w.writeMsgPack(keys[i]) != nil || w.writeMsgPack(vals[i]) != nil {
+					break
+				}
+			}
+
+		default:
+			w.SetError(fmt.Errorf("bin.MsgPack-Error: unsupported type %T", v))
+		}
+	}
+	return w.err
+}
+
+// msgpackHead writes header of string, binary, array or map of length n
+func (w *Writer) msgpackHead(fix byte, fixMax int, c8, c16, c32 byte, n int) {
+	switch {
+	case n <= fixMax:
+		w.WriteByte(fix | byte(n))
+	case c8 != 0 && n <= math.MaxUint8:
+		w.write([]byte{c8, byte(n)})
+	case n <= math.MaxUint16:
+		w.WriteByte(c16)
+		w.WriteUint16(uint16(n))
+	default:
+		w.WriteByte(c32)
+		w.WriteUint32(uint32(n))
+	}
+}
+
+func (w *Writer) msgpackBytes(b []byte) {
+	if b == nil {
+		w.WriteByte(0xc0)
+		return
+	}
+	w.msgpackHead(0, -1, 0xc4, 0xc5, 0xc6, len(b))
+	w.Write(b)
+}
+
+func (w *Writer) msgpackUint(u uint64) {
+	switch {
+	case u < 0x80:
+		w.WriteByte(byte(u))
+	case u <= math.MaxUint8:
+		w.write([]byte{0xcc, byte(u)})
+	case u <= math.MaxUint16:
+		w.WriteByte(0xcd)
+		w.WriteUint16(uint16(u))
+	case u <= math.MaxUint32:
+		w.WriteByte(0xce)
+		w.WriteUint32(uint32(u))
+	default:
+		w.WriteByte(0xcf)
+		w.WriteUint64(u)
+	}
+}
+
+func (w *Writer) msgpackInt(i int64) {
+	switch {
+	case i >= 0:
+		w.msgpackUint(uint64(i))
+	case i >= -32:
+		w.WriteByte(byte(i))
+	case i >= math.MinInt8:
+		w.write([]byte{0xd0, byte(i)})
+	case i >= math.MinInt16:
+		w.WriteByte(0xd1)
+		w.WriteUint16(uint16(i))
+	case i >= math.MinInt32:
+		w.WriteByte(0xd2)
+		w.WriteUint32(uint32(i))
+	default:
+		w.WriteByte(0xd3)
+		w.WriteUint64(uint64(i))
+	}
+}
+
+func (w *Writer) msgpackExt(typ int8, data []byte) {
+	switch n := len(data); n {
+	case 1, 2, 4, 8, 16: // fixext
+		w.WriteByte(0xd4 + byte(bits.TrailingZeros(uint(n))))
+	default:
+		w.msgpackHead(0, -1, 0xc7, 0xc8, 0xc9, n)
+	}
+	w.WriteByte(byte(typ))
+	w.Write(data)
+}
+
+// msgpackTime writes time as timestamp extension (32, 64 or 96 bits)
+func (w *Writer) msgpackTime(t time.Time) {
+	sec, nsec := t.Unix(), uint64(t.Nanosecond())
+	switch {
+	case sec>>34 != 0:
+		w.msgpackExt(msgpackExtTime, append(Uint32ToBytes(uint32(nsec)), Uint64ToBytes(uint64(sec))...))
+	case nsec == 0 && sec>>32 == 0:
+		w.msgpackExt(msgpackExtTime, Uint32ToBytes(uint32(sec)))
+	default:
+		w.msgpackExt(msgpackExtTime, Uint64ToBytes(nsec<<34|uint64(sec)))
+	}
+}
+
+func (w *Writer) msgpackBigInt(x *big.Int) {
+	var buf bytes.Buffer
+	(&Writer{wr: &buf}).WriteBigInt(x)
+	w.msgpackExt(MsgPackExtBigInt, buf.Bytes())
+}
+
+//----------- MessagePack reader --------------
+
+func (r *Reader) readMsgPackVar(val any) error {
+	p := reflect.ValueOf(val)
+	if p.Kind() != reflect.Ptr || p.IsNil() {
+		r.SetError(errIncompatibleType)
+		return r.err
+	}
+	v, err := r.readMsgPack()
+	if err == nil {
+		r.SetError(r.setDecoded(p.Elem(), v))
+	}
+	return r.err
+}
+
+// readMsgPack reads any MessagePack value
+func (r *Reader) readMsgPack() (any, error) {
+	defer r.leave()
+	if r.enter(errMsgPackCorrupted) != nil {
+		return nil, r.err
+	}
+	b, err := r.ReadByte()
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case b <= 0x7f:
+		return int64(b), nil
+	case b >= 0xe0:
+		return int64(int8(b)), nil
+	case b&0xe0 == 0xa0:
+		return r.msgpackString(int(b & 0x1f))
+	case b&0xf0 == 0x90:
+		return r.msgpackArray(int(b & 0x0f))
+	case b&0xf0 == 0x80:
+		return r.msgpackMap(int(b & 0x0f))
+	}
+	switch b {
+	case 0xc0:
+		return nil, nil
+	case 0xc2:
+		return false, nil
+	case 0xc3:
+		return true, nil
+	case 0xc4, 0xc5, 0xc6:
+		return r.read(r.msgpackLen(b - 0xc4))
+	case 0xc7, 0xc8, 0xc9:
+		return r.msgpackExt(r.msgpackLen(b - 0xc7))
+	case 0xca:
+		return r.ReadFloat32()
+	case 0xcb:
+		return r.ReadFloat64()
+	case 0xcc:
+		u, err := r.ReadUint8()
+		return int64(u), err
+	case 0xcd:
+		u, err := r.ReadUint16()
+		return int64(u), err
+	case 0xce:
+		u, err := r.ReadUint32()
+		return int64(u), err
+	case 0xcf:
+		u, err := r.ReadUint64()
+		if u > math.MaxInt64 {
+			return u, err
+		}
+		return int64(u), err
+	case 0xd0:
+		i, err := r.ReadUint8()
+		return int64(int8(i)), err
+	case 0xd1:
+		i, err := r.ReadUint16()
+		return int64(int16(i)), err
+	case 0xd2:
+		i, err := r.ReadUint32()
+		return int64(int32(i)), err
+	case 0xd3:
+		i, err := r.ReadUint64()
+		return int64(i), err
+	case 0xd4, 0xd5, 0xd6, 0xd7, 0xd8:
+		return r.msgpackExt(1 << (b - 0xd4))
+	case 0xd9, 0xda, 0xdb:
+		return r.msgpackString(r.msgpackLen(b - 0xd9))
+	case 0xdc, 0xdd:
+		return r.msgpackArray(r.msgpackLen(b - 0xdc + 1))
+	case 0xde, 0xdf:
+		return r.msgpackMap(r.msgpackLen(b - 0xde + 1))
+	}
+	r.SetError(errMsgPackCorrupted)
+	return nil, r.err
+}
+
+// msgpackLen reads length of 1, 2 or 4 bytes (size = 0, 1, 2)
+func (r *Reader) msgpackLen(size byte) int {
+	var n uint32
+	switch size {
+	case 0:
+		u, _ := r.ReadUint8()
+		n = uint32(u)
+	case 1:
+		u, _ := r.ReadUint16()
+		n = uint32(u)
+	default:
+		n, _ = r.ReadUint32()
+	}
+	return int(n)
+}
+
+func (r *Reader) msgpackString(n int) (string, error) {
+	b, err := r.read(n)
+	return string(b), err
+}
+
+// msgpackArray reads n items; length comes from input, so array grows as items are read
+func (r *Reader) msgpackArray(n int) (any, error) {
+	arr := make([]any, 0, preallocLen(n, 16))
+	for i := 0; i < n && r.err == nil; i++ {
+		v, _ := r.readMsgPack()
+		arr = append(arr, v)
+	}
+	return arr, r.err
+}
+
+func (r *Reader) msgpackMap(n int) (any, error) {
+	keys, vals := make([]any, 0, preallocLen(n, 32)), make([]any, 0, preallocLen(n, 32))
+	for i := 0; i < n && r.err == nil; i++ {
+		k, _ := r.readMsgPack()
+		v, _ := r.readMsgPack()
+		keys, vals = append(keys, k), append(vals, v)
+	}
+	if r.err != nil {
+		return nil, r.err
+	}
+	m, err := newDecodedMap(keys, vals)
+	r.SetError(err)
+	return m, err
+}
+
+func (r *Reader) msgpackExt(n int) (any, error) {
+	typ, _ := r.ReadUint8()
+	data, err := r.read(n)
+	if err != nil {
+		return nil, err
+	}
+	switch int8(typ) {
+	case msgpackExtTime:
+		switch n {
+		case 4:
+			return time.Unix(int64(BytesToUint32(data)), 0), nil
+		case 8:
+			u := BytesToUint64(data)
+			return time.Unix(int64(u&(1<<34-1)), int64(u>>34)), nil
+		case 12:
+			return time.Unix(int64(BytesToUint64(data[4:])), int64(BytesToUint32(data))), nil
+		}
+	case MsgPackExtBigInt:
+		rr := Reader{rd: bytes.NewReader(data)}
+		return rr.ReadBigInt()
+	}
+	r.SetError(errMsgPackExt)
+	return nil, r.err
+}
+
+// skipMsgPack skips next MessagePack value
+func (r *Reader) skipMsgPack() error {
+	defer r.leave()
+	if r.enter(errMsgPackCorrupted) != nil {
+		return r.err
+	}
+	b, err := r.ReadByte()
+	if err != nil {
+		return err
+	}
+	items := 0 // number of nested items
+	switch {
+	case b <= 0x7f || b >= 0xe0:
+		return nil
+	case b&0xe0 == 0xa0:
+		return r.discard(int(b & 0x1f))
+	case b&0xf0 == 0x90:
+		items = int(b & 0x0f)
+	case b&0xf0 == 0x80:
+		items = 2 * int(b&0x0f)
+	default:
+		switch b {
+		case 0xc0, 0xc2, 0xc3:
+			return nil
+		case 0xc4, 0xc5, 0xc6:
+			return r.discard(r.msgpackLen(b - 0xc4))
+		case 0xc7, 0xc8, 0xc9:
+			return r.discard(r.msgpackLen(b-0xc7) + 1)
+		case 0xca, 0xce, 0xd2:
+			return r.discard(4)
+		case 0xcb, 0xcf, 0xd3:
+			return r.discard(8)
+		case 0xcc, 0xd0:
+			return r.discard(1)
+		case 0xcd, 0xd1:
+			return r.discard(2)
+		case 0xd4, 0xd5, 0xd6, 0xd7, 0xd8:
+			return r.discard(1<<(b-0xd4) + 1)
+		case 0xd9, 0xda, 0xdb:
+			return r.discard(r.msgpackLen(b - 0xd9))
+		case 0xdc, 0xdd:
+			items = r.msgpackLen(b - 0xdc + 1)
+		case 0xde, 0xdf:
+			items = 2 * r.msgpackLen(b-0xde+1)
+		default:
+			r.SetError(errMsgPackCorrupted)
+		}
+	}
+	for i := 0; i < items && r.err == nil; i++ {
+		r.skipMsgPack()
+	}
+	return r.err
+}
@@ -0,0 +1,215 @@
+package bin
+
+import (
+	"encoding/hex"
+	"math"
+	"math/big"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func encodeMsgPack(values ...any) []byte {
+	w := NewBuffer(nil)
+	w.SetFormat(FormatMsgPack)
+	w.WriteVar(values...)
+	return w.Bytes()
+}
+
+func decodeMsgPack(data []byte, values ...any) error {
+	r := NewBuffer(data)
+	r.SetFormat(FormatMsgPack)
+	return r.ReadVar(values...)
+}
+
+func TestMsgPack_encoding(t *testing.T) {
+	for _, c := range []struct {
+		v   any
+		exp []byte
+	}{
+		{nil, []byte{0xc0}},
+		{true, []byte{0xc3}},
+		{1, []byte{0x01}},
+		{-1, []byte{0xff}},
+		{-33, []byte{0xd0, 0xdf}},
+		{uint8(200), []byte{0xcc, 0xc8}},
+		{-1000, []byte{0xd1, 0xfc, 0x18}},
+		{65536, []byte{0xce, 0, 1, 0, 0}},
+		{uint64(math.MaxUint64), []byte{0xcf, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}},
+		{math.MinInt64, []byte{0xd3, 0x80, 0, 0, 0, 0, 0, 0, 0}},
+		{1.5, []byte{0xcb, 0x3f, 0xf8, 0, 0, 0, 0, 0, 0}},
+		{float32(1.5), []byte{0xca, 0x3f, 0xc0, 0, 0}},
+		{"abc", []byte{0xa3, 'a', 'b', 'c'}},
+		{[]byte{1}, []byte{0xc4, 0x01, 0x01}},
+		{[]int{1, 2}, []byte{0x92, 0x01, 0x02}},
+		{[]int(nil), []byte{0xc0}},
+		{map[string]int{"a": 1}, []byte{0x81, 0xa1, 'a', 0x01}},
+		{time.Unix(1, 0), []byte{0xd6, 0xff, 0, 0, 0, 1}},
+		{time.Unix(1, 1), []byte{0xd7, 0xff, 0, 0, 0, 0x04, 0, 0, 0, 0x01}},
+		{time.Unix(1<<34, 0), []byte{0xc7, 0x0c, 0xff, 0, 0, 0, 0, 0, 0, 0, 0x04, 0, 0, 0, 0}},
+		{big.NewInt(-1), []byte{0xd5, 0x01, 0xc1, 0x01}},
+	} {
+		assert.Equal(t, c.exp, encodeMsgPack(c.v), c.v)
+	}
+}
+
+func TestMsgPack_values(t *testing.T) {
+	tm := time.Unix(1700000000, 123)
+	bi, _ := new(big.Int).SetString("-123456789012345678901234567890", 10)
+	str := string(make([]byte, 300))
+
+	data := encodeMsgPack(-5, uint64(math.MaxUint64), 0.25, "abc", str, []byte(nil), []byte{}, tm, bi, []string{"a", "b"}, map[int]bool{1: true}, time.Second)
+	var (
+		i   int
+		u   uint64
+		f   float32
+		s   string
+		s2  string
+		b1  = []byte{1}
+		b2  []byte
+		tt  time.Time
+		bb  *big.Int
+		ss  []string
+		mp  map[int]bool
+		dur time.Duration
+	)
+	err := decodeMsgPack(data, &i, &u, &f, &s, &s2, &b1, &b2, &tt, &bb, &ss, &mp, &dur)
+
+	assert.NoError(t, err)
+	assert.Equal(t, -5, i)
+	assert.Equal(t, uint64(math.MaxUint64), u)
+	assert.Equal(t, float32(0.25), f)
+	assert.Equal(t, "abc", s)
+	assert.Equal(t, str, s2)
+	assert.Nil(t, b1)
+	assert.Equal(t, []byte{}, b2)
+	assert.Equal(t, tm, tt)
+	assert.Equal(t, bi.String(), bb.String())
+	assert.Equal(t, []string{"a", "b"}, ss)
+	assert.Equal(t, map[int]bool{1: true}, mp)
+	assert.Equal(t, time.Second, dur)
+}
+
+func TestMsgPack_struct(t *testing.T) {
+	type Obj struct {
+		testHeader
+		Name  string
+		User  *User
+		Point *Point
+		Amt   big.Int
+		Hash  H256
+		Parts []testPart
+		Tags  map[string][]int
+	}
+	obj := Obj{
+		testHeader: testHeader{ID: 1, CreatedAt: time.Unix(1700000000, 0)},
+		Name:       "obj",
+		User:       &User{ID: 2, Name: "user"},
+		Amt:        *big.NewInt(100),
+		Hash:       HashH256("abc"),
+		Parts:      []testPart{{"a", 1}, {"b", 2}},
+		Tags:       map[string][]int{"x": {1, 2}},
+	}
+
+	data := encodeMsgPack(obj)
+	var res Obj
+	err := decodeMsgPack(data, &res)
+
+	assert.NoError(t, err)
+	assert.Equal(t, obj, res)
+}
+
+func TestMsgPack_struct_shadowedNames(t *testing.T) {
+	type Obj struct {
+		testHeader
+		ID   string // hides testHeader.ID
+		Name string
+	}
+	obj := Obj{testHeader{1, time.Unix(1700000000, 0)}, "id", "obj"}
+
+	data := encodeMsgPack(obj)
+	var m map[string]any
+	var res Obj
+	err1 := decodeMsgPack(data, &m)
+	err2 := decodeMsgPack(data, &res)
+
+	assert.NoError(t, err1)
+	assert.NoError(t, err2)
+	assert.Equal(t, byte(0x83), data[0]) // map of 3 unique keys
+	assert.Equal(t, map[string]any{"CreatedAt": obj.CreatedAt, "ID": "id", "Name": "obj"}, m)
+	assert.Equal(t, Obj{testHeader{0, obj.CreatedAt}, "id", "obj"}, res)
+}
+
+func TestMsgPack_struct_strict(t *testing.T) {
+	type obj struct {
+		A int
+		b int
+	}
+	w := NewBuffer(nil)
+	w.SetFormat(FormatMsgPack)
+	w.SetMode(ModeStrictStructs)
+	err1 := w.WriteVar(obj{1, 2})
+
+	r := NewBuffer(encodeMsgPack(map[string]int{"A": 1}))
+	r.SetFormat(FormatMsgPack)
+	r.SetMode(ModeStrictStructs)
+	err2 := r.ReadVar(&obj{})
+
+	assert.ErrorContains(t, err1, "bin.obj.b")
+	assert.ErrorContains(t, err2, "bin.obj.b")
+}
+
+func TestMsgPack_decodeAny(t *testing.T) {
+	data := encodeMsgPack(map[string]any{"a": []any{1, "x", nil, true}, "b": map[int]string{1: "y"}})
+
+	var res any
+	err := decodeMsgPack(data, &res)
+
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]any{
+		"a": []any{int64(1), "x", nil, true},
+		"b": map[any]any{int64(1): "y"},
+	}, res)
+}
+
+func TestMsgPack_errors(t *testing.T) {
+	var s string
+	var i int8
+	var a any
+
+	err1 := decodeMsgPack([]byte{0xc1}, &a)
+	err2 := decodeMsgPack([]byte{0xa3, 'a'}, &s)
+	err3 := decodeMsgPack([]byte{0xcd, 0x01, 0x00}, &i)
+	err4 := decodeMsgPack([]byte{0x01}, &s)
+	err5 := decodeMsgPack([]byte{0xd4, 0x05, 0x00}, &a)
+
+	assert.Error(t, err1)
+	assert.Error(t, err2)
+	assert.Error(t, err3)
+	assert.Error(t, err4)
+	assert.Error(t, err5)
+}
+
+func TestMsgPack_corruptedLengths(t *testing.T) {
+	var ms runtime.MemStats
+	runtime.ReadMemStats(&ms)
+	alloc := ms.TotalAlloc
+
+	for _, data := range []string{"c6ffffffff", "dbffffffff", "c9ffffffff01", "ddffffffff", "dfffffffff",
+		"c5ffff", "daffff", "dcffff", "deffff", "dd7fffffffc0c0", "df7fffffffa161c0"} {
+		b, _ := hex.DecodeString(data)
+		var a any
+		err := decodeMsgPack(b, &a)
+
+		r := NewBuffer(b)
+		r.SetFormat(FormatMsgPack)
+		err2 := r.SkipVar(nil)
+
+		assert.Error(t, err, data)
+		assert.Error(t, err2, data)
+	}
+	runtime.ReadMemStats(&ms)
+	assert.Less(t, ms.TotalAlloc-alloc, uint64(10<<20)) // lengths are not allocated up front
+}
@@ -0,0 +1,83 @@
+package bin
+
+import "encoding/json"
+
+// Optional is a value that may be absent.
+// It is encoded as presence byte followed by value (if present).
+type Optional[T any] struct {
+	value T
+	ok    bool
+}
+
+func Some[T any](v T) Optional[T] {
+	return Optional[T]{v, true}
+}
+
+func None[T any]() Optional[T] {
+	return Optional[T]{}
+}
+
+// Value returns value or zero value of T if absent
+func (o Optional[T]) Value() T {
+	return o.value
+}
+
+// Ok returns true if value is present
+func (o Optional[T]) Ok() bool {
+	return o.ok
+}
+
+// Get returns value and presence flag
+func (o Optional[T]) Get() (T, bool) {
+	return o.value, o.ok
+}
+
+// ValueOr returns value or def if absent
+func (o Optional[T]) ValueOr(def T) T {
+	if o.ok {
+		return o.value
+	}
+	return def
+}
+
+func (o *Optional[T]) Set(v T) {
+	o.value, o.ok = v, true
+}
+
+func (o *Optional[T]) Clear() {
+	*o = Optional[T]{}
+}
+
+func (o Optional[T]) BinWrite(w *Writer) {
+	if w.WriteBool(o.ok) == nil && o.ok {
+		w.WriteVar(o.value)
+	}
+}
+
+func (o *Optional[T]) BinRead(r *Reader) {
+	o.Clear()
+	if ok, err := r.ReadBool(); err == nil && ok {
+		if r.ReadVar(&o.value) == nil {
+			o.ok = true
+		}
+	}
+}
+
+func (o Optional[T]) MarshalJSON() ([]byte, error) {
+	if !o.ok {
+		return []byte("null"), nil
+	}
+	return json.Marshal(o.value)
+}
+
+func (o *Optional[T]) UnmarshalJSON(data []byte) error {
+	o.Clear()
+	if string(data) == "null" {
+		return nil
+	}
+	if err := json.Unmarshal(data, &o.value); err != nil {
+		return err
+	}
+	o.ok = true
+	return nil
+}
@@ -0,0 +1,96 @@
+package bin
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOptional(t *testing.T) {
+	a := Some(0)
+	b := None[int]()
+
+	v, ok := a.Get()
+
+	assert.True(t, a.Ok())
+	assert.False(t, b.Ok())
+	assert.Equal(t, 0, v)
+	assert.True(t, ok)
+	assert.Equal(t, 5, b.ValueOr(5))
+	b.Set(7)
+	assert.Equal(t, 7, b.Value())
+	b.Clear()
+	assert.False(t, b.Ok())
+}
+
+func TestOptional_Encode(t *testing.T) {
+	data := Encode(Some(0), None[int](), Some("abc"), Some(Some[int64](-1)), []Optional[uint64]{Some[uint64](1), {}})
+
+	var (
+		a   = Some(5)
+		b   = Some(5)
+		c   Optional[string]
+		d   Optional[Optional[int64]]
+		arr []Optional[uint64]
+	)
+	err := Decode(data, &a, &b, &c, &d, &arr)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{1, 0, 0, 1, 3, 'a', 'b', 'c', 1, 1, 0xc1, 1, 2, 1, 1, 0}, data)
+	assert.Equal(t, Some(0), a)
+	assert.Equal(t, None[int](), b)
+	assert.Equal(t, Some("abc"), c)
+	assert.Equal(t, Some(Some[int64](-1)), d)
+	assert.Equal(t, []Optional[uint64]{Some[uint64](1), {}}, arr)
+}
+
+func TestOptional_Struct(t *testing.T) {
+	type Msg struct {
+		Limit Optional[int]
+		Name  Optional[string]
+	}
+	msg := Msg{Limit: Some(0)}
+	w := NewBuffer(nil)
+	w.SetMode(ModeStructs)
+
+	w.WriteVar(msg)
+	var res Msg
+	err := w.ReadVar(&res)
+
+	assert.NoError(t, err)
+	assert.Equal(t, msg, res)
+}
+
+func TestOptional_JSON(t *testing.T) {
+	vv := []Optional[int]{Some(0), None[int]()}
+
+	data, err := json.Marshal(vv)
+	var res []Optional[int]
+	err2 := json.Unmarshal(data, &res)
+
+	assert.NoError(t, err)
+	assert.NoError(t, err2)
+	assert.Equal(t, `[0,null]`, string(data))
+	assert.Equal(t, vv, res)
+}
+
+func TestOptional_CorruptedLength(t *testing.T) {
+	data := append([]byte{1}, Encode(int64(1)<<61)...) // present value with corrupted length
+
+	var o1 Optional[[]int]
+	var o2 Optional[[]byte]
+	var o3 Optional[[]string]
+	var o4 Optional[[][]byte]
+	err1 := Decode(data, &o1)
+	err2 := Decode(data, &o2)
+	err3 := Decode(data, &o3)
+	err4 := Decode(data, &o4)
+
+	assert.Error(t, err1)
+	assert.Error(t, err2)
+	assert.Error(t, err3)
+	assert.Error(t, err4)
+	assert.False(t, o1.Ok())
+	assert.False(t, o2.Ok())
+}
@@ -0,0 +1,180 @@
+package proto
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+
+	"github.com/denisskin/bin"
+)
+
+// Marshal returns protobuf encoding of struct v
+func Marshal(v any) ([]byte, error) {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Ptr && !rv.IsNil() {
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return nil, errNotStruct
+	}
+	return marshal(rv)
+}
+
+func marshal(rv reflect.Value) ([]byte, error) {
+	buf := bin.NewBuffer(nil)
+	buf.SetMode(bin.ModeLEB128)
+	writeMessage(&buf.Writer, rv)
+	return buf.Bytes(), buf.Writer.Error()
+}
+
+func writeMessage(w *bin.Writer, rv reflect.Value) {
+	m := getMessage(rv.Type())
+	if m.err != nil {
+		w.SetError(m.err)
+		return
+	}
+	for _, f := range m.fields {
+		fv, err := rv.FieldByIndexErr(f.index)
+		if err != nil {
+			continue // field of nil embedded pointer
+		}
+		if f.write(w, fv); w.Error() != nil {
+			return
+		}
+	}
+}
+
+func writeTag(w *bin.Writer, num, wire int) {
+	w.WriteVarUint64(uint64(num)<<3 | uint64(wire))
+}
+
+func (f *field) write(w *bin.Writer, v reflect.Value) {
+	switch t := v.Type(); t.Kind() {
+	case reflect.Ptr:
+		if !v.IsNil() {
+			f.writeValue(w, f.num, v.Elem()) // explicit presence: zero value is written too
+		}
+
+	case reflect.Slice:
+		if v.Len() == 0 {
+			return
+		}
+		if t.Elem().Kind() == reflect.Uint8 {
+			f.writeValue(w, f.num, v)
+		} else if isScalar(t.Elem()) && !f.unpacked {
+			buf := bin.NewBuffer(nil)
+			buf.SetMode(bin.ModeLEB128)
+			for i := 0; i < v.Len(); i++ {
+				f.writeScalar(&buf.Writer, v.Index(i))
+			}
+			writeTag(w, f.num, wireBytes)
+			w.WriteBytes(buf.Bytes())
+		} else {
+			for i := 0; i < v.Len(); i++ {
+				f.writeValue(w, f.num, v.Index(i))
+			}
+		}
+
+	case reflect.Map:
+		iter := v.MapRange()
+		for iter.Next() {
+			buf := bin.NewBuffer(nil)
+			buf.SetMode(bin.ModeLEB128)
+			f.writeValue(&buf.Writer, 1, iter.Key())
+			f.writeValue(&buf.Writer, 2, iter.Value())
+			if err := buf.Writer.Error(); err != nil {
+				w.SetError(err)
+				return
+			}
+			writeTag(w, f.num, wireBytes)
+			w.WriteBytes(buf.Bytes())
+		}
+
+	default:
+		if !v.IsZero() {
+			f.writeValue(w, f.num, v)
+		}
+	}
+}
+
+// writeValue writes tag and value of non-repeated field
+func (f *field) writeValue(w *bin.Writer, num int, v reflect.Value) {
+	switch v.Kind() {
+	case reflect.String:
+		writeTag(w, num, wireBytes)
+		w.WriteString(v.String())
+
+	case reflect.Slice:
+		if v.Type().Elem().Kind() != reflect.Uint8 {
+			w.SetError(fmt.Errorf("bin.proto-Error: unsupported type %s of field %s", v.Type(), f.name))
+			return
+		}
+		writeTag(w, num, wireBytes)
+		w.WriteBytes(v.Bytes())
+
+	case reflect.Ptr:
+		if v.IsNil() {
+			v = reflect.New(v.Type().Elem())
+		}
+		f.writeValue(w, num, v.Elem())
+
+	case reflect.Struct:
+		data, err := marshal(v)
+		if err != nil {
+			w.SetError(err)
+			return
+		}
+		writeTag(w, num, wireBytes)
+		w.WriteBytes(data)
+
+	default:
+		if !isScalar(v.Type()) {
+			w.SetError(fmt.Errorf("bin.proto-Error: unsupported type %s of field %s", v.Type(), f.name))
+			return
+		}
+		writeTag(w, num, f.wireType(v.Type()))
+		f.writeScalar(w, v)
+	}
+}
+
+// writeScalar writes scalar value without tag
+func (f *field) writeScalar(w *bin.Writer, v reflect.Value) {
+	switch v.Kind() {
+	case reflect.Bool:
+		if v.Bool() {
+			w.WriteVarUint64(1)
+		} else {
+			w.WriteVarUint64(0)
+		}
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		switch f.wireType(v.Type()) {
+		case wireFixed32:
+			w.WriteUint32LE(uint32(v.Int()))
+		case wireFixed64:
+			w.WriteUint64LE(uint64(v.Int()))
+		default:
+			if f.zigzag {
+				w.WriteVarInt64(v.Int())
+			} else {
+				w.WriteVarUint64(uint64(v.Int()))
+			}
+		}
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		switch f.wireType(v.Type()) {
+		case wireFixed32:
+			w.WriteUint32LE(uint32(v.Uint()))
+		case wireFixed64:
+			w.WriteUint64LE(v.Uint())
+		default:
+			w.WriteVarUint64(v.Uint())
+		}
+
+	case reflect.Float32:
+		w.WriteUint32LE(math.Float32bits(float32(v.Float())))
+
+	case reflect.Float64:
+		w.WriteUint64LE(math.Float64bits(v.Float()))
+	}
+}
@@ -0,0 +1,125 @@
+// Package proto encodes and decodes Go structs in protobuf wire format using struct tags,
+// without generated code.
+//
+// Fields are declared with tag `proto:"N[,option...]"` where N is field number. Options:
+//
+//	zigzag   - signed integer as sint32/sint64
+//	fixed    - integer as fixed32/fixed64 (sfixed32/sfixed64 for signed types)
+//	unpacked - repeated scalar field is written unpacked
+//
+// Supported field types: bool, integers, floats, string, []byte, structs and pointers to structs
+// (submessages), pointers to scalars (fields with explicit presence), slices (repeated fields)
+// and maps (map entries). Fields without tag are ignored.
+// Like proto3, zero scalar values are not written.
+package proto
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/denisskin/bin"
+)
+
+const (
+	wireVarint  = 0
+	wireFixed64 = 1
+	wireBytes   = 2
+	wireFixed32 = 5
+)
+
+var (
+	errNotStruct     = errors.New("bin.proto-Error: value must be struct or pointer to struct")
+	errWireType      = errors.New("bin.proto-Error: invalid wire type")
+	errTruncated     = errors.New("bin.proto-Error: unexpected end of data")
+	errInvalidNumber = errors.New("bin.proto-Error: invalid field number")
+)
+
+type field struct {
+	num      int
+	name     string
+	index    []int
+	zigzag   bool
+	fixed    bool
+	unpacked bool
+}
+
+type message struct {
+	fields []*field // sorted by number
+	byNum  map[int]*field
+	err    error
+}
+
+var messages sync.Map // reflect.Type -> *message
+
+func getMessage(t reflect.Type) *message {
+	if m, ok := messages.Load(t); ok {
+		return m.(*message)
+	}
+	m := &message{byNum: map[int]*field{}}
+	for _, sf := range bin.StructFields(t) {
+		tag := sf.Tag.Get("proto")
+		if tag == "" || tag == "-" {
+			continue
+		}
+		opts := strings.Split(tag, ",")
+		f := &field{name: sf.Name, index: sf.Index}
+		f.num, _ = strconv.Atoi(opts[0])
+		if f.num <= 0 || f.num >= 1<<29 || m.byNum[f.num] != nil {
+			m.err = fmt.Errorf("%w %q in %s.%s", errInvalidNumber, opts[0], t, sf.Name)
+			break
+		}
+		for _, opt := range opts[1:] {
+			switch opt {
+			case "zigzag":
+				f.zigzag = true
+			case "fixed":
+				f.fixed = true
+			case "unpacked":
+				f.unpacked = true
+			}
+		}
+		m.fields = append(m.fields, f)
+		m.byNum[f.num] = f
+	}
+	sort.Slice(m.fields, func(i, j int) bool { return m.fields[i].num < m.fields[j].num })
+	messages.Store(t, m)
+	return m
+}
+
+// isScalar returns true if values of type can be packed
+func isScalar(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
+
+// wireType returns wire type of non-repeated value of type
+func (f *field) wireType(t reflect.Type) int {
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if !f.fixed {
+			return wireVarint
+		} else if t.Bits() <= 32 {
+			return wireFixed32
+		}
+		return wireFixed64
+	case reflect.Bool:
+		return wireVarint
+	case reflect.Float32:
+		return wireFixed32
+	case reflect.Float64:
+		return wireFixed64
+	}
+	return wireBytes
+}
@@ -0,0 +1,196 @@
+package proto
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type test1 struct {
+	A int32 `proto:"1"`
+}
+
+type test3 struct {
+	C *test1 `proto:"3"`
+}
+
+type testScalars struct {
+	I32    int32   `proto:"1"`
+	I64    int64   `proto:"2"`
+	U32    uint32  `proto:"3"`
+	U64    uint64  `proto:"4"`
+	S32    int32   `proto:"5,zigzag"`
+	S64    int64   `proto:"6,zigzag"`
+	F32    uint32  `proto:"7,fixed"`
+	F64    uint64  `proto:"8,fixed"`
+	SF32   int32   `proto:"9,fixed"`
+	SF64   int64   `proto:"10,fixed"`
+	Float  float32 `proto:"11"`
+	Double float64 `proto:"12"`
+	Bool   bool    `proto:"13"`
+	Str    string  `proto:"14"`
+	Bytes  []byte  `proto:"15"`
+}
+
+type testMessage struct {
+	ID       uint64           `proto:"1"`
+	Name     string           `proto:"2"`
+	Inner    test1            `proto:"3"`
+	Ptr      *test1           `proto:"4"`
+	List     []*test1         `proto:"5"`
+	Packed   []int64          `proto:"6"`
+	Unpacked []uint32         `proto:"7,unpacked"`
+	Zigzag   []int32          `proto:"8,zigzag"`
+	Strs     []string         `proto:"9"`
+	Map      map[string]int32 `proto:"10"`
+	MsgMap   map[int64]*test1 `proto:"11"`
+	Opt      *int32           `proto:"12"`
+	Ignored  string
+	Skipped  string `proto:"-"`
+}
+
+func TestMarshal_wireFormat(t *testing.T) {
+	for _, c := range []struct {
+		v   any
+		exp []byte
+	}{
+		{test1{150}, []byte{0x08, 0x96, 0x01}},
+		{test1{-1}, []byte{0x08, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0x01}},
+		{test1{0}, nil},
+		{struct {
+			B string `proto:"2"`
+		}{"testing"}, []byte{0x12, 0x07, 't', 'e', 's', 't', 'i', 'n', 'g'}},
+		{test3{&test1{150}}, []byte{0x1a, 0x03, 0x08, 0x96, 0x01}},
+		{struct {
+			D []int32 `proto:"4"`
+		}{[]int32{3, 270, 86942}}, []byte{0x22, 0x06, 0x03, 0x8e, 0x02, 0x9e, 0xa7, 0x05}},
+		{struct {
+			D []int32 `proto:"4,unpacked"`
+		}{[]int32{3, 270}}, []byte{0x20, 0x03, 0x20, 0x8e, 0x02}},
+		{struct {
+			S int32 `proto:"1,zigzag"`
+		}{-2}, []byte{0x08, 0x03}},
+		{struct {
+			F uint32 `proto:"1,fixed"`
+		}{1}, []byte{0x0d, 1, 0, 0, 0}},
+		{struct {
+			D float64 `proto:"1"`
+		}{1}, []byte{0x09, 0, 0, 0, 0, 0, 0, 0xf0, 0x3f}},
+		{struct {
+			M map[string]int32 `proto:"5"`
+		}{map[string]int32{"a": 1}}, []byte{0x2a, 0x05, 0x0a, 0x01, 'a', 0x10, 0x01}},
+		{struct {
+			P *int32 `proto:"1"`
+		}{new(int32)}, []byte{0x08, 0x00}},
+	} {
+		data, err := Marshal(c.v)
+
+		assert.NoError(t, err)
+		assert.Equal(t, c.exp, data)
+	}
+}
+
+func TestMarshal_scalars(t *testing.T) {
+	v := testScalars{
+		I32:    math.MinInt32,
+		I64:    math.MinInt64,
+		U32:    math.MaxUint32,
+		U64:    math.MaxUint64,
+		S32:    math.MinInt32,
+		S64:    math.MinInt64,
+		F32:    math.MaxUint32,
+		F64:    math.MaxUint64,
+		SF32:   -1,
+		SF64:   math.MinInt64,
+		Float:  1.5,
+		Double: -math.Pi,
+		Bool:   true,
+		Str:    "abc",
+		Bytes:  []byte{1, 2, 3},
+	}
+
+	data, err := Marshal(&v)
+	var res testScalars
+	err2 := Unmarshal(data, &res)
+
+	assert.NoError(t, err)
+	assert.NoError(t, err2)
+	assert.Equal(t, v, res)
+}
+
+func TestMarshal_message(t *testing.T) {
+	opt := int32(0)
+	v := testMessage{
+		ID:       1,
+		Name:     "name",
+		Inner:    test1{5},
+		Ptr:      &test1{6},
+		List:     []*test1{{1}, {}, {3}},
+		Packed:   []int64{-1, 0, 1 << 40},
+		Unpacked: []uint32{1, 2},
+		Zigzag:   []int32{-1, 1},
+		Strs:     []string{"a", "", "c"},
+		Map:      map[string]int32{"a": 1, "b": 0},
+		MsgMap:   map[int64]*test1{-1: {7}},
+		Opt:      &opt,
+		Ignored:  "ignored",
+		Skipped:  "skipped",
+	}
+
+	data, err := Marshal(v)
+	var res testMessage
+	err2 := Unmarshal(data, &res)
+
+	assert.NoError(t, err)
+	assert.NoError(t, err2)
+	v.Ignored, v.Skipped = "", ""
+	assert.Equal(t, v, res)
+}
+
+func TestUnmarshal_unknownFields(t *testing.T) {
+	var v test1
+	data := []byte{
+		0x10, 0x96, 0x01, // field 2 varint
+		0x19, 1, 2, 3, 4, 5, 6, 7, 8, // field 3 fixed64
+		0x22, 0x02, 'a', 'b', // field 4 bytes
+		0x2d, 1, 2, 3, 4, // field 5 fixed32
+		0x08, 0x96, 0x01, // field 1
+	}
+
+	err := Unmarshal(data, &v)
+
+	assert.NoError(t, err)
+	assert.Equal(t, test1{150}, v)
+}
+
+func TestUnmarshal_packedAndUnpacked(t *testing.T) {
+	var v struct {
+		D []int32 `proto:"4"`
+	}
+
+	err := Unmarshal([]byte{0x22, 0x02, 0x03, 0x04, 0x20, 0x05}, &v)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []int32{3, 4, 5}, v.D)
+}
+
+func TestUnmarshal_errors(t *testing.T) {
+	var v testMessage
+
+	err1 := Unmarshal([]byte{0x12, 0x07, 't', 'e'}, &v) // truncated string
+	err2 := Unmarshal([]byte{0x08, 0x96}, &v)           // truncated varint
+	err3 := Unmarshal([]byte{0x0a, 0x00}, &v)           // wrong wire type
+	err4 := Unmarshal([]byte{0x0b}, &v)                 // group
+	err5 := Unmarshal(nil, v)
+	_, err6 := Marshal(struct {
+		A int `proto:"0"`
+	}{})
+
+	assert.Error(t, err1)
+	assert.Error(t, err2)
+	assert.Error(t, err3)
+	assert.Error(t, err4)
+	assert.Error(t, err5)
+	assert.Error(t, err6)
+}
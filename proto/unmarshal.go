@@ -0,0 +1,239 @@
+package proto
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"reflect"
+
+	"github.com/denisskin/bin"
+)
+
+// Unmarshal parses protobuf encoded data into struct pointed by v.
+// Unknown fields are skipped, repeated fields are appended.
+func Unmarshal(data []byte, v any) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return errNotStruct
+	}
+	return unmarshal(data, rv.Elem())
+}
+
+type decoder struct {
+	*bin.Reader
+	buf *bytes.Reader
+}
+
+func newDecoder(data []byte) *decoder {
+	buf := bytes.NewReader(data)
+	r := bin.NewReader(buf)
+	r.SetMode(bin.ModeLEB128)
+	return &decoder{r, buf}
+}
+
+func (d *decoder) more() bool {
+	return d.buf.Len() > 0 && d.Error() == nil
+}
+
+func (d *decoder) readBytes() []byte {
+	n, err := d.ReadVarUint64()
+	if err != nil {
+		return nil
+	}
+	if n > uint64(d.buf.Len()) {
+		d.SetError(errTruncated)
+		return nil
+	}
+	b := make([]byte, n)
+	d.Read(b)
+	return b
+}
+
+func (d *decoder) skip(wire int) {
+	switch wire {
+	case wireVarint:
+		d.ReadVarUint64()
+	case wireFixed64:
+		d.ReadUint64LE()
+	case wireBytes:
+		d.readBytes()
+	case wireFixed32:
+		d.ReadUint32LE()
+	default:
+		d.SetError(errWireType)
+	}
+}
+
+func unmarshal(data []byte, rv reflect.Value) error {
+	m := getMessage(rv.Type())
+	if m.err != nil {
+		return m.err
+	}
+	d := newDecoder(data)
+	for d.more() {
+		tag, err := d.ReadVarUint64()
+		if err != nil {
+			break
+		}
+		num, wire := int(tag>>3), int(tag&7)
+		if f := m.byNum[num]; f != nil {
+			f.read(d, wire, fieldByIndex(rv, f.index))
+		} else {
+			d.skip(wire)
+		}
+	}
+	return d.Error()
+}
+
+func (f *field) read(d *decoder, wire int, v reflect.Value) {
+	switch t := v.Type(); t.Kind() {
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.Uint8 {
+			f.readValue(d, wire, v)
+		} else if wire == wireBytes && isScalar(t.Elem()) {
+			// packed
+			pd := newDecoder(d.readBytes())
+			for pd.more() {
+				e := reflect.New(t.Elem()).Elem()
+				f.readScalar(pd, e)
+				v.Set(reflect.Append(v, e))
+			}
+			d.SetError(pd.Error())
+		} else {
+			e := reflect.New(t.Elem()).Elem()
+			if f.readValue(d, wire, e); d.Error() == nil {
+				v.Set(reflect.Append(v, e))
+			}
+		}
+
+	case reflect.Map:
+		if wire != wireBytes {
+			d.SetError(errWireType)
+			return
+		}
+		key := reflect.New(t.Key()).Elem()
+		val := reflect.New(t.Elem()).Elem()
+		ed := newDecoder(d.readBytes())
+		for ed.more() {
+			tag, _ := ed.ReadVarUint64()
+			switch num, wire := int(tag>>3), int(tag&7); num {
+			case 1:
+				f.readValue(ed, wire, key)
+			case 2:
+				f.readValue(ed, wire, val)
+			default:
+				ed.skip(wire)
+			}
+		}
+		if d.SetError(ed.Error()); d.Error() == nil {
+			if v.IsNil() {
+				v.Set(reflect.MakeMap(t))
+			}
+			v.SetMapIndex(key, val)
+		}
+
+	default:
+		f.readValue(d, wire, v)
+	}
+}
+
+// readValue reads value of non-repeated field
+func (f *field) readValue(d *decoder, wire int, v reflect.Value) {
+	t := v.Type()
+	if t.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			v.Set(reflect.New(t.Elem()))
+		}
+		f.readValue(d, wire, v.Elem())
+		return
+	}
+	if wire != f.wireType(t) {
+		d.SetError(errWireType)
+		return
+	}
+	switch t.Kind() {
+	case reflect.String:
+		v.SetString(string(d.readBytes()))
+
+	case reflect.Slice:
+		if t.Elem().Kind() != reflect.Uint8 {
+			d.SetError(fmt.Errorf("bin.proto-Error: unsupported type %s of field %s", t, f.name))
+			return
+		}
+		v.SetBytes(d.readBytes())
+
+	case reflect.Struct:
+		if data := d.readBytes(); d.Error() == nil {
+			d.SetError(unmarshal(data, v))
+		}
+
+	default:
+		if !isScalar(t) {
+			d.SetError(fmt.Errorf("bin.proto-Error: unsupported type %s of field %s", t, f.name))
+			return
+		}
+		f.readScalar(d, v)
+	}
+}
+
+// readScalar reads scalar value without tag
+func (f *field) readScalar(d *decoder, v reflect.Value) {
+	switch v.Kind() {
+	case reflect.Bool:
+		u, _ := d.ReadVarUint64()
+		v.SetBool(u != 0)
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		switch f.wireType(v.Type()) {
+		case wireFixed32:
+			u, _ := d.ReadUint32LE()
+			v.SetInt(int64(int32(u)))
+		case wireFixed64:
+			u, _ := d.ReadUint64LE()
+			v.SetInt(int64(u))
+		default:
+			if f.zigzag {
+				i, _ := d.ReadVarInt64()
+				v.SetInt(i)
+			} else {
+				u, _ := d.ReadVarUint64()
+				v.SetInt(int64(u))
+			}
+		}
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		switch f.wireType(v.Type()) {
+		case wireFixed32:
+			u, _ := d.ReadUint32LE()
+			v.SetUint(uint64(u))
+		case wireFixed64:
+			u, _ := d.ReadUint64LE()
+			v.SetUint(u)
+		default:
+			u, _ := d.ReadVarUint64()
+			v.SetUint(u)
+		}
+
+	case reflect.Float32:
+		u, _ := d.ReadUint32LE()
+		v.SetFloat(float64(math.Float32frombits(u)))
+
+	case reflect.Float64:
+		u, _ := d.ReadUint64LE()
+		v.SetFloat(math.Float64frombits(u))
+	}
+}
+
+// fieldByIndex returns nested field of struct v; nil embedded pointers are allocated.
+func fieldByIndex(v reflect.Value, index []int) reflect.Value {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v
+}
@@ -0,0 +1,68 @@
+package bin
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"reflect"
+)
+
+// RawValue is an encoded value. It allows to defer decoding of values or to forward them without re-encoding.
+//
+// In FormatMsgPack and FormatCBOR raw value is a complete data item. It is written verbatim,
+// and ReadVar captures exact bytes of the next data item without decoding it.
+// RawValue fields of structs, slices and maps are decoded and encoded again, so their bytes may differ from original.
+//
+// In FormatBin raw value is written verbatim as well, but values are not self-describing,
+// so it can not be read by ReadVar. Use Reader.ReadRaw to capture encoding of a value of known type.
+type RawValue []byte
+
+var typeRawValue = reflect.TypeOf(RawValue(nil))
+
+var errRawValueType = errors.New("bin.RawValue-Error: value of unknown type can not be read in FormatBin (use Reader.ReadRaw)")
+
+// Decode decodes raw value of native format
+func (v RawValue) Decode(values ...any) error {
+	return Decode(v, values...)
+}
+
+// ReadRaw skips a value of the given type and returns its encoded bytes
+func (r *Reader) ReadRaw(typ reflect.Type) (RawValue, error) {
+	raw := r.capture(func() {
+		r.SkipVar(typ)
+	})
+	return raw, r.err
+}
+
+// capture returns bytes read by fn
+func (r *Reader) capture(fn func()) RawValue {
+	var buf bytes.Buffer
+	rd := r.rd
+	r.rd = io.TeeReader(rd, &buf)
+	fn()
+	r.rd = rd
+	return buf.Bytes()
+}
+
+func (r *Reader) readRawValue(v *RawValue) error {
+	switch r.format {
+	case FormatMsgPack:
+		*v = r.capture(func() { r.skipMsgPack() })
+	case FormatCBOR:
+		*v = r.capture(func() { r.skipCBOR() })
+	default:
+		r.SetError(errRawValueType)
+	}
+	if r.err != nil {
+		*v = nil
+	}
+	return r.err
+}
+
+func (w *Writer) writeRawValue(v RawValue) error {
+	if v == nil && w.format != FormatBin {
+		return w.writeVar(nil)
+	}
+	_, err := w.Write(v)
+	return err
+}
@@ -0,0 +1,109 @@
+package bin
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRawValue_MsgPack(t *testing.T) {
+	body := map[string]any{"b": []int{1, 2}, "a": "x", "t": time.Unix(1, 0)}
+	data := encodeMsgPack("header", body, 123)
+
+	// router: reads header and forwards body without decoding
+	var (
+		header string
+		raw    RawValue
+		n      int
+	)
+	err := decodeMsgPack(data, &header, &raw, &n)
+	fwd := encodeMsgPack(header, raw, n)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "header", header)
+	assert.Equal(t, 123, n)
+	assert.Equal(t, data[len(encodeMsgPack("header")):len(data)-1], []byte(raw))
+	assert.Equal(t, data, fwd)
+}
+
+func TestRawValue_CBOR(t *testing.T) {
+	body := map[string]any{"b": []int{1, 2}, "a": "x"}
+	data := encodeCBOR("header", body, RawValue(nil))
+
+	var (
+		header string
+		raw    RawValue
+		null   any
+		res    map[string]any
+	)
+	err := decodeCBOR(data, &header, &raw, &null)
+	err2 := decodeCBOR(raw, &res)
+
+	assert.NoError(t, err)
+	assert.NoError(t, err2)
+	assert.Equal(t, encodeCBOR(body), []byte(raw))
+	assert.Nil(t, null)
+	assert.Equal(t, data, encodeCBOR(header, raw, nil))
+	assert.Equal(t, []any{int64(1), int64(2)}, res["b"])
+}
+
+func TestRawValue_nested(t *testing.T) {
+	type Msg struct {
+		Kind string
+		Body RawValue
+	}
+	msg := Msg{"test", encodeCBOR([]any{1, "a"})}
+
+	data := encodeCBOR(msg)
+	var res Msg
+	err := decodeCBOR(data, &res)
+
+	assert.NoError(t, err)
+	assert.Equal(t, msg, res)
+}
+
+func TestRawValue_Bin(t *testing.T) {
+	user := &User{ID: 1, Name: "user"}
+	data := Encode("header", user, 12345)
+
+	// router: reads header and forwards body of known type without decoding
+	r := NewBuffer(data)
+	header, _ := r.ReadString()
+	raw, err := r.ReadRaw(reflect.TypeOf(user))
+	n, _ := r.ReadVarInt()
+	var res *User
+	err2 := raw.Decode(&res)
+
+	var raw2 RawValue
+	err3 := Decode(Encode(12345), &raw2)
+
+	assert.NoError(t, err)
+	assert.NoError(t, err2)
+	assert.Equal(t, user, res)
+	assert.Equal(t, 12345, n)
+	assert.Equal(t, data, Encode(header, raw, n)) // written verbatim
+	assert.Equal(t, Encode(1), Encode(RawValue(nil), 1))
+	assert.ErrorIs(t, err3, errRawValueType)
+	assert.Nil(t, raw2)
+}
+
+func TestReader_ReadRaw(t *testing.T) {
+	pts := []Point{{1, 2}, {3, 4}}
+	data := Encode("header", pts, "tail")
+
+	r := NewBuffer(data)
+	header, _ := r.ReadString()
+	raw, err := r.ReadRaw(reflect.TypeOf(pts))
+	tail, _ := r.ReadString()
+	var res []Point
+	err2 := raw.Decode(&res)
+
+	assert.NoError(t, err)
+	assert.NoError(t, err2)
+	assert.Equal(t, "header", header)
+	assert.Equal(t, "tail", tail)
+	assert.Equal(t, pts, res)
+	assert.Equal(t, Encode(pts), []byte(raw))
+}
@@ -2,18 +2,27 @@ package bin
 
 import (
 	"encoding"
+	"encoding/binary"
 	"encoding/gob"
 	"errors"
 	"io"
 	"math"
 	"math/big"
+	"net"
+	"net/netip"
+	"net/url"
 	"reflect"
+	"slices"
 	"time"
 )
 
 type Reader struct {
 	rd         io.Reader
 	err        error
+	mode       Mode
+	format     Format
+	strs       []string // string table (ModeStringTable)
+	depth      int      // nesting depth of data items in self-describing formats
 	CntRead    int64
 	maxCntRead int64
 }
@@ -77,10 +86,34 @@ func (r *Reader) Read(buf []byte) (n int, err error) {
 	return
 }
 
+// maxPrealloc limits memory allocated for data that has not been read yet (lengths come from input)
+const maxPrealloc = 1 << 16
+
+// preallocLen returns number of items of given size to allocate before n items are read
+func preallocLen(n, size int) int {
+	return min(n, maxPrealloc/max(size, 1))
+}
+
 func (r *Reader) read(length int) ([]byte, error) {
-	buf := make([]byte, length)
-	_, err := r.Read(buf)
-	return buf, err
+	if length < 0 {
+		r.SetError(errBinaryDataWasCorrupted)
+		return nil, r.err
+	}
+	if length <= maxPrealloc {
+		buf := make([]byte, length)
+		_, err := r.Read(buf)
+		return buf, err
+	}
+	// large data is read in growing chunks, so corrupted length fails on end of data without huge allocation
+	buf := make([]byte, 0, maxPrealloc)
+	for len(buf) < length {
+		n := min(length-len(buf), max(len(buf), maxPrealloc))
+		buf = slices.Grow(buf, n)[:len(buf)+n]
+		if _, err := r.Read(buf[len(buf)-n:]); err != nil {
+			return nil, err
+		}
+	}
+	return buf, nil
 }
 
 //----------- fixed types --------------
@@ -107,6 +140,21 @@ func (r *Reader) ReadUint64() (uint64, error) {
 	return BytesToUint64(b), err
 }
 
+func (r *Reader) ReadUint16LE() (uint16, error) {
+	b, err := r.read(2)
+	return BytesToUint16LE(b), err
+}
+
+func (r *Reader) ReadUint32LE() (uint32, error) {
+	b, err := r.read(4)
+	return BytesToUint32LE(b), err
+}
+
+func (r *Reader) ReadUint64LE() (uint64, error) {
+	b, err := r.read(8)
+	return BytesToUint64LE(b), err
+}
+
 func (r *Reader) ReadFloat32() (float32, error) {
 	b, err := r.read(4)
 	return math.Float32frombits(BytesToUint32(b)), err
@@ -117,6 +165,28 @@ func (r *Reader) ReadFloat64() (float64, error) {
 	return math.Float64frombits(BytesToUint64(b)), err
 }
 
+func (r *Reader) ReadComplex64() (complex64, error) {
+	re, _ := r.ReadFloat32()
+	im, err := r.ReadFloat32()
+	return complex(re, im), err
+}
+
+func (r *Reader) ReadComplex128() (complex128, error) {
+	re, _ := r.ReadFloat64()
+	im, err := r.ReadFloat64()
+	return complex(re, im), err
+}
+
+func (r *Reader) ReadFloat16() (Float16, error) {
+	v, err := r.ReadUint16()
+	return Float16(v), err
+}
+
+func (r *Reader) ReadBFloat16() (BFloat16, error) {
+	v, err := r.ReadUint16()
+	return BFloat16(v), err
+}
+
 func (r *Reader) ReadTime() (time.Time, error) {
 	v, err := r.ReadUint64()
 	return time.Unix(0, int64(v)), err
@@ -142,10 +212,20 @@ func (r *Reader) ReadBool() (bool, error) {
 
 //----------- var types ----------------
 func (r *Reader) readVarInt() (i int64) {
+	if r.mode&ModeLEB128 != 0 {
+		i, err := binary.ReadVarint(r)
+		r.setErr(err)
+		return i
+	}
 	b0, err := r.ReadUint8()
 	if err != nil {
 		return
 	}
+	return r.readVarIntTail(b0)
+}
+
+// readVarIntTail reads rest of var int with the first byte b0
+func (r *Reader) readVarIntTail(b0 byte) (i int64) {
 	if b0&0x80 == 0 {
 		return int64(b0)
 	}
@@ -194,6 +274,264 @@ func (r *Reader) ReadBigInt() (i *big.Int, err error) {
 	return
 }
 
+// ReadTimeFull reads time written by Writer.WriteTimeFull
+func (r *Reader) ReadTimeFull() (t time.Time, err error) {
+	kind, err := r.ReadByte()
+	if err != nil || kind == timeZero {
+		return
+	}
+	l := r.readLocation(kind)
+	sec, _ := r.ReadVarInt64()
+	nsec, err := r.ReadVarInt64()
+	if err != nil {
+		return
+	}
+	t = time.Unix(sec, nsec)
+	return t.In(l.location(t)), nil
+}
+
+// timeLocation is location written by Writer.writeLocation
+type timeLocation struct {
+	kind   byte
+	offset int
+	zone   string
+}
+
+func (r *Reader) readLocation(kind byte) (l timeLocation) {
+	switch l.kind = kind; kind {
+	case timeUTC, timeLocal:
+	case timeZone:
+		l.offset, _ = r.ReadVarInt()
+		l.zone, _ = r.ReadString()
+	default:
+		r.SetError(errBinaryDataWasCorrupted)
+	}
+	return
+}
+
+// location returns location of time t.
+// Zone from tz database is used if it is known and has the written offset at t.
+func (l timeLocation) location(t time.Time) *time.Location {
+	switch l.kind {
+	case timeUTC:
+		return time.UTC
+	case timeZone:
+		if loc := locations.load(l.zone); loc != nil && loc != time.Local {
+			if _, off := t.In(loc).Zone(); off == l.offset {
+				return loc
+			}
+		}
+		return time.FixedZone(l.zone, l.offset)
+	}
+	return time.Local
+}
+
+func (r *Reader) ReadTimeSec() (time.Time, error) {
+	v, err := r.ReadVarInt64()
+	return time.Unix(v, 0), err
+}
+
+func (r *Reader) ReadDate() (Date, error) {
+	v, err := r.ReadVarInt64()
+	return Date(v), err
+}
+
+func (r *Reader) ReadUint128() (x Uint128, err error) {
+	b, err := r.read(16)
+	return Uint128FromBytes(b), err
+}
+
+func (r *Reader) ReadUint256() (x Uint256, err error) {
+	b, err := r.read(32)
+	return Uint256FromBytes(b), err
+}
+
+func (r *Reader) ReadVarUint128() (x Uint128, err error) {
+	var buf [16]byte
+	if err = r.readVarUint(buf[:]); err == nil {
+		x = Uint128FromBytes(buf[:])
+	}
+	return
+}
+
+func (r *Reader) ReadVarUint256() (x Uint256, err error) {
+	var buf [32]byte
+	if err = r.readVarUint(buf[:]); err == nil {
+		x = Uint256FromBytes(buf[:])
+	}
+	return
+}
+
+// readVarUint reads unsigned integer written by WriteBigInt into big-endian buffer
+func (r *Reader) readVarUint(buf []byte) error {
+	b0, err := r.ReadUint8()
+	if err != nil {
+		return err
+	}
+	if b0&0x80 == 0 {
+		buf[len(buf)-1] = b0
+		return nil
+	}
+	n := int(b0 & 0x3f)
+	if b0&0x40 != 0 || n > len(buf) {
+		r.SetError(errUintOverflow)
+		return r.err
+	}
+	_, err = r.Read(buf[len(buf)-n:])
+	return err
+}
+
+func (r *Reader) ReadBigRat() (*big.Rat, error) {
+	num, _ := r.ReadBigInt()
+	denom, err := r.ReadBigInt()
+	if err != nil {
+		return nil, err
+	}
+	if denom.Sign() == 0 {
+		r.SetError(errBinaryDataWasCorrupted)
+		return nil, r.err
+	}
+	return new(big.Rat).SetFrac(num, denom), nil
+}
+
+func (r *Reader) ReadBigFloat() (*big.Float, error) {
+	h, _ := r.ReadByte()
+	prec, _ := r.ReadVarUint64()
+	mode, err := r.ReadByte()
+	if err != nil {
+		return nil, err
+	}
+	if prec > big.MaxPrec || mode > byte(big.ToPositiveInf) || h&^(bigFloatNeg|bigFloatFinite|bigFloatInf) != 0 {
+		r.SetError(errBinaryDataWasCorrupted)
+		return nil, r.err
+	}
+	x := new(big.Float).SetPrec(uint(prec)).SetMode(big.RoundingMode(mode))
+	switch {
+	case h&bigFloatInf != 0:
+		x.SetInf(h&bigFloatNeg != 0)
+	case h&bigFloatFinite != 0:
+		exp, _ := r.ReadVarInt()
+		m, err := r.ReadBigInt()
+		if err != nil {
+			return nil, err
+		}
+		if h&bigFloatNeg != 0 {
+			m.Neg(m)
+		}
+		x.SetInt(m).SetMantExp(x, exp)
+	case h&bigFloatNeg != 0:
+		x.Neg(x)
+	}
+	return x, nil
+}
+
+func (r *Reader) ReadAddr() (a netip.Addr, err error) {
+	family, err := r.ReadByte()
+	if err != nil {
+		return
+	}
+	switch family {
+	case addrNone, addrIPv4, addrIPv6, addrIPv6Zone:
+		return r.readAddr(family)
+	}
+	err = r.readLegacyAddr(family, &a)
+	return
+}
+
+func (r *Reader) readAddr(family byte) (a netip.Addr, err error) {
+	switch family {
+	case addrIPv4:
+		var b [4]byte
+		if _, err = r.Read(b[:]); err == nil {
+			a = netip.AddrFrom4(b)
+		}
+	case addrIPv6, addrIPv6Zone:
+		var b [16]byte
+		if _, err = r.Read(b[:]); err == nil {
+			a = netip.AddrFrom16(b)
+		}
+		if family == addrIPv6Zone {
+			var zone string
+			zone, err = r.ReadString()
+			a = a.WithZone(zone)
+		}
+	}
+	return
+}
+
+// maxLegacyAddrLen limits length of legacy netip values (address, zone name and port or bits)
+const maxLegacyAddrLen = 1024
+
+// readLegacyAddr reads netip value written as WriteBytes(MarshalBinary()); b0 is the first byte of length
+func (r *Reader) readLegacyAddr(b0 byte, v encoding.BinaryUnmarshaler) error {
+	if n := r.readVarIntTail(b0); n < 0 || n > maxLegacyAddrLen {
+		r.SetError(errBinaryDataWasCorrupted)
+	} else if buf, err := r.read(int(n)); err == nil && v.UnmarshalBinary(buf) != nil {
+		r.SetError(errBinaryDataWasCorrupted)
+	}
+	return r.err
+}
+
+func (r *Reader) ReadPrefix() (p netip.Prefix, err error) {
+	family, err := r.ReadByte()
+	if err != nil || family == addrNone {
+		return
+	}
+	if family != addrIPv4 && family != addrIPv6 {
+		err = r.readLegacyAddr(family, &p)
+		return
+	}
+	a, _ := r.readAddr(family)
+	bits, err := r.ReadByte()
+	if err != nil {
+		return
+	}
+	if p = netip.PrefixFrom(a, int(bits)); !p.IsValid() {
+		r.SetError(errBinaryDataWasCorrupted)
+		err = r.err
+	}
+	return
+}
+
+func (r *Reader) ReadAddrPort() (ap netip.AddrPort, err error) {
+	family, err := r.ReadByte()
+	if err != nil || family == addrNone {
+		return
+	}
+	switch family {
+	case addrIPv4, addrIPv6, addrIPv6Zone:
+		a, _ := r.readAddr(family)
+		port, err := r.ReadUint16()
+		return netip.AddrPortFrom(a, port), err
+	}
+	err = r.readLegacyAddr(family, &ap)
+	return
+}
+
+// ReadIP reads address written by WriteIP or WriteAddr. IPv4 address is returned in 16-byte form.
+func (r *Reader) ReadIP() (net.IP, error) {
+	a, err := r.ReadAddr()
+	if err != nil || !a.IsValid() {
+		return nil, err
+	}
+	if a.Is4() {
+		b := a.As4()
+		return net.IPv4(b[0], b[1], b[2], b[3]), nil
+	}
+	return net.IP(a.AsSlice()), nil
+}
+
+// ReadURL reads url written by WriteURL (empty string is read as nil)
+func (r *Reader) ReadURL() (*url.URL, error) {
+	s, err := r.ReadString()
+	if err != nil || s == "" {
+		return nil, err
+	}
+	u, err := url.Parse(s)
+	r.SetError(err)
+	return u, err
+}
+
 func (r *Reader) ReadVarInt() (int, error) {
 	v := r.readVarInt()
 	return int(v), r.err
@@ -205,48 +543,195 @@ func (r *Reader) ReadVarInt64() (int64, error) {
 }
 
 func (r *Reader) ReadVarUint64() (uint64, error) {
-	v := r.readVarInt()
-	return uint64(v), r.err
+	v := r.readUvarint()
+	return v, r.err
+}
+
+func (r *Reader) readUvarint() uint64 {
+	if r.mode&ModeLEB128 != 0 {
+		v, err := binary.ReadUvarint(r)
+		r.setErr(err)
+		return v
+	}
+	return uint64(r.readVarInt())
+}
+
+// setErr sets error if reader has no error yet
+func (r *Reader) setErr(err error) {
+	if err != nil && r.err == nil {
+		r.err = err
+	}
+}
+
+// readVarLen reads length prefix written by writeLen
+func (r *Reader) readVarLen() (int, error) {
+	if r.mode&ModeLEB128 != 0 {
+		n, err := r.ReadVarUint64()
+		return int(n), err
+	}
+	return r.ReadVarInt()
+}
+
+// readLen reads length written by writeLen. It returns -1 if value must be read as nil.
+func (r *Reader) readLen() (int, error) {
+	n, err := r.readVarLen()
+	if err != nil {
+		return -1, err
+	}
+	if n == 0 && r.mode&ModeNilEmpty == 0 || n == -1 && r.mode&ModeNilEmpty != 0 {
+		return -1, nil
+	}
+	if n < 0 {
+		r.SetError(errBinaryDataWasCorrupted)
+		return -1, r.err
+	}
+	return n, nil
 }
 
 func (r *Reader) ReadSliceBytes() ([][]byte, error) {
-	n, err := r.ReadVarInt()
-	if err != nil || n == 0 {
+	n, err := r.readLen()
+	if err != nil || n < 0 {
 		return nil, err
 	}
-	res := make([][]byte, n)
+	res := make([][]byte, 0, preallocLen(n, 24))
 	for i := 0; i < n; i++ {
-		if res[i], err = r.ReadBytes(); err != nil {
+		b, err := r.ReadBytes()
+		if err != nil {
 			return nil, err
 		}
+		res = append(res, b)
+	}
+	return res, nil
+}
+
+func (r *Reader) ReadFloat16s() ([]Float16, error) {
+	return readUint16s[Float16](r)
+}
+
+func (r *Reader) ReadBFloat16s() ([]BFloat16, error) {
+	return readUint16s[BFloat16](r)
+}
+
+func (r *Reader) ReadDeltaInts() ([]int64, error) {
+	return r.readDeltas(1)
+}
+
+func (r *Reader) ReadDelta2Ints() ([]int64, error) {
+	return r.readDeltas(2)
+}
+
+func (r *Reader) ReadDeltaUints() ([]uint64, error) {
+	vv, err := r.readDeltas(1)
+	return intsToUints(vv), err
+}
+
+func (r *Reader) ReadDeltaTimes() ([]time.Time, error) {
+	return r.readDeltaTimes(1)
+}
+
+func (r *Reader) ReadDelta2Times() ([]time.Time, error) {
+	return r.readDeltaTimes(2)
+}
+
+func (r *Reader) readDeltas(order int) ([]int64, error) {
+	n, err := r.readLen()
+	if err != nil || n < 0 {
+		return nil, err
+	}
+	return r.readDeltaValues(n, order)
+}
+
+// readDeltaValues reads n values written by Writer.writeDeltaValues
+func (r *Reader) readDeltaValues(n, order int) ([]int64, error) {
+	vv := make([]int64, 0, preallocLen(n, 8))
+	for i := 0; i < n; i++ {
+		v, err := r.ReadVarInt64()
+		if err != nil {
+			return nil, err
+		}
+		vv = append(vv, v)
+	}
+	fromDeltas(vv, order)
+	return vv, nil
+}
+
+// readDeltaTimes reads times written by Writer.writeDeltaTimes; all times get location of the first time
+func (r *Reader) readDeltaTimes(order int) ([]time.Time, error) {
+	n, err := r.readLen()
+	if err != nil || n < 0 {
+		return nil, err
+	} else if n == 0 {
+		return []time.Time{}, nil
+	}
+	kind, _ := r.ReadByte()
+	l := r.readLocation(kind)
+	secs, _ := r.readDeltaValues(n, order)
+	nsecs, err := r.readDeltaValues(n, order)
+	if err != nil {
+		return nil, err
+	}
+	tt := make([]time.Time, n)
+	loc := l.location(time.Unix(secs[0], nsecs[0]))
+	for i := range tt {
+		if t := time.Unix(secs[i], nsecs[i]); !t.IsZero() {
+			tt[i] = t.In(loc)
+		}
+	}
+	return tt, nil
+}
+
+func readUint16s[T ~uint16](r *Reader) ([]T, error) {
+	n, err := r.readLen()
+	if err != nil || n < 0 {
+		return nil, err
+	}
+	if n > math.MaxInt/2 {
+		r.SetError(errBinaryDataWasCorrupted)
+		return nil, r.err
+	}
+	b, err := r.read(2 * n)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]T, n)
+	for i := range res {
+		res[i] = T(BytesToUint16(b[2*i:]))
 	}
 	return res, nil
 }
 
 func (r *Reader) ReadBytes() ([]byte, error) {
-	if n, err := r.ReadVarInt(); err != nil {
+	if n, err := r.readLen(); err != nil || n < 0 {
 		return nil, err
-	} else if n > 0 {
+	} else {
 		return r.read(n)
 	}
-	return nil, nil
 }
 
 func (r *Reader) ReadString() (string, error) {
-	v, err := r.ReadBytes()
-	return string(v), err
+	if r.mode&ModeStringTable != 0 {
+		return r.readStringRef()
+	}
+	if n, err := r.readVarLen(); err != nil || n <= 0 {
+		return "", err
+	} else {
+		v, err := r.read(n)
+		return string(v), err
+	}
 }
 
 func (r *Reader) ReadStrings() ([]string, error) {
-	n, err := r.ReadVarInt()
-	if err != nil || n == 0 {
+	n, err := r.readLen()
+	if err != nil || n < 0 {
 		return nil, err
 	}
-	res := make([]string, n)
+	res := make([]string, 0, preallocLen(n, 16))
 	for i := 0; i < n; i++ {
-		if res[i], err = r.ReadString(); err != nil {
+		s, err := r.ReadString()
+		if err != nil {
 			return nil, err
 		}
+		res = append(res, s)
 	}
 	return res, nil
 }
@@ -260,13 +745,16 @@ func (r *Reader) ReadError() (error, error) {
 }
 
 func (r *Reader) readSlice(p reflect.Value) {
-	if n, err := r.ReadVarInt(); err == nil {
-		if n == 0 {
+	if n, err := r.readLen(); err == nil {
+		if n < 0 {
 			p.Set(reflect.Zero(p.Type()))
 			return
 		}
-		slice := reflect.MakeSlice(p.Type(), n, n)
+		// slice grows as items are read, so corrupted length does not allocate memory for n items
+		elem := reflect.Zero(p.Type().Elem())
+		slice := reflect.MakeSlice(p.Type(), 0, preallocLen(n, int(elem.Type().Size())))
 		for i := 0; i < n && r.err == nil; i++ {
+			slice = reflect.Append(slice, elem)
 			r.ReadVar(slice.Index(i).Addr().Interface())
 		}
 		if r.err == nil {
@@ -276,8 +764,8 @@ func (r *Reader) readSlice(p reflect.Value) {
 }
 
 func (r *Reader) readMap(p reflect.Value) {
-	if n, err := r.ReadVarInt(); err == nil {
-		if n == 0 {
+	if n, err := r.readLen(); err == nil {
+		if n < 0 {
 			p.Set(reflect.Zero(p.Type()))
 			return
 		}
@@ -305,6 +793,15 @@ func (r *Reader) ReadVar(val ...interface{}) error {
 }
 
 func (r *Reader) readVar(val interface{}) error {
+	if v, ok := val.(*RawValue); ok {
+		return r.readRawValue(v)
+	}
+	switch r.format {
+	case FormatMsgPack:
+		return r.readMsgPackVar(val)
+	case FormatCBOR:
+		return r.readCBORVar(val)
+	}
 	switch v := val.(type) {
 	case *int:
 		*v = int(r.readVarInt())
@@ -318,22 +815,42 @@ func (r *Reader) readVar(val interface{}) error {
 		*v = int64(r.readVarInt())
 
 	case *uint:
-		*v = uint(r.readVarInt())
+		*v = uint(r.readUvarint())
 	case *uint8:
-		*v = uint8(r.readVarInt())
+		*v = uint8(r.readUvarint())
 	case *uint16:
-		*v = uint16(r.readVarInt())
+		*v = uint16(r.readUvarint())
 	case *uint32:
-		*v = uint32(r.readVarInt())
+		*v = uint32(r.readUvarint())
 	case *uint64:
-		*v = uint64(r.readVarInt())
+		*v = uint64(r.readUvarint())
 
 	case *float32:
 		*v, _ = r.ReadFloat32()
 	case *float64:
 		*v, _ = r.ReadFloat64()
+	case *complex64:
+		*v, _ = r.ReadComplex64()
+	case *complex128:
+		*v, _ = r.ReadComplex128()
+	case *Float16:
+		*v, _ = r.ReadFloat16()
+	case *BFloat16:
+		*v, _ = r.ReadBFloat16()
+	case *[]Float16:
+		*v, _ = r.ReadFloat16s()
+	case *[]BFloat16:
+		*v, _ = r.ReadBFloat16s()
 	case *time.Time:
-		*v, _ = r.ReadTime()
+		if r.mode&ModeTimeFull != 0 {
+			*v, _ = r.ReadTimeFull()
+		} else {
+			*v, _ = r.ReadTime()
+		}
+	case *time.Duration:
+		*v = time.Duration(r.readVarInt())
+	case *Date:
+		*v, _ = r.ReadDate()
 	case *bool:
 		*v, _ = r.ReadBool()
 
@@ -354,6 +871,40 @@ func (r *Reader) readVar(val interface{}) error {
 		if x, err := r.ReadBigInt(); err == nil {
 			v.Set(x)
 		}
+	case **big.Rat:
+		*v, _ = r.ReadBigRat()
+	case *big.Rat:
+		if x, err := r.ReadBigRat(); err == nil {
+			v.Set(x)
+		}
+	case **big.Float:
+		*v, _ = r.ReadBigFloat()
+	case *big.Float:
+		if x, err := r.ReadBigFloat(); err == nil {
+			v.SetPrec(x.Prec()).SetMode(x.Mode()).Set(x)
+		}
+	case *netip.Addr:
+		*v, _ = r.ReadAddr()
+	case *netip.Prefix:
+		*v, _ = r.ReadPrefix()
+	case *netip.AddrPort:
+		*v, _ = r.ReadAddrPort()
+	case *net.IP:
+		*v, _ = r.ReadIP()
+	case **url.URL:
+		*v, _ = r.ReadURL()
+	case *url.URL:
+		if u, err := r.ReadURL(); err == nil {
+			if u == nil {
+				u = new(url.URL)
+			}
+			*v = *u
+		}
+
+	case *Uint128:
+		*v, _ = r.ReadVarUint128()
+	case *Uint256:
+		*v, _ = r.ReadVarUint256()
 
 	case binaryDecoder:
 		r.err = v.BinaryDecode(r.rd)
@@ -379,6 +930,17 @@ func (r *Reader) readVar(val interface{}) error {
 			p := pp.Elem()
 			switch p.Kind() {
 			case reflect.Ptr:
+				if r.mode&ModeStructs != 0 && isPlainStruct(p.Type().Elem()) {
+					if ok, err := r.ReadBool(); err != nil || !ok {
+						p.Set(reflect.Zero(p.Type()))
+						return r.err
+					}
+					obj := reflect.New(p.Type().Elem())
+					if r.readStruct(obj.Elem()); r.err == nil {
+						p.Set(obj)
+					}
+					return r.err
+				}
 				// read object in case:  var obj*Object; r.Read(&obj)
 				buf, err := r.ReadBytes()
 				if err != nil {
@@ -403,6 +965,12 @@ func (r *Reader) readVar(val interface{}) error {
 			case reflect.Slice:
 				r.readSlice(p)
 				return r.err
+
+			case reflect.Struct:
+				if r.mode&ModeStructs != 0 {
+					r.readStruct(p)
+					return r.err
+				}
 			}
 		}
 
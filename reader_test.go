@@ -2,6 +2,12 @@ package bin
 
 import (
 	"bytes"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"net"
+	"net/netip"
+	"net/url"
 	"testing"
 	"time"
 
@@ -236,6 +242,488 @@ func TestReader_ReadLimit_Fail(t *testing.T) {
 	assert.Error(t, err)
 }
 
+func TestReader_ReadUint32LE(t *testing.T) {
+	w := NewBuffer(nil)
+	w.WriteUint32LE(0x01020304)
+	w.WriteUint64LE(0x0102030405060708)
+
+	data := w.Bytes()
+	r := w.Reader
+	v32, err32 := r.ReadUint32LE()
+	v64, err64 := r.ReadUint64LE()
+
+	assert.Equal(t, []byte{4, 3, 2, 1, 8, 7, 6, 5, 4, 3, 2, 1}, data)
+	assert.NoError(t, err32)
+	assert.NoError(t, err64)
+	assert.Equal(t, uint32(0x01020304), v32)
+	assert.Equal(t, uint64(0x0102030405060708), v64)
+}
+
+func TestReader_ReadTimeFull(t *testing.T) {
+	nyc, err := time.LoadLocation("America/New_York")
+	assert.NoError(t, err)
+	times := []time.Time{
+		{},
+		time.Date(2016, 07, 06, 18, 24, 45, 123456789, time.UTC),
+		time.Date(1000, 01, 01, 0, 0, 0, 1, time.UTC),
+		time.Date(9999, 12, 31, 23, 59, 59, 999999999, time.UTC),
+		time.Date(2016, 07, 06, 18, 24, 45, 0, time.Local),
+		time.Date(2016, 07, 06, 18, 24, 45, 0, nyc),
+		time.Date(2016, 01, 06, 18, 24, 45, 0, nyc),
+		time.Date(2016, 07, 06, 18, 24, 45, 0, time.FixedZone("XYZ", 3*3600)),
+		time.Now(),
+	}
+	w := NewBuffer(nil)
+	for _, tm := range times {
+		w.WriteTimeFull(tm)
+	}
+
+	r := w.Reader
+	for _, tm := range times {
+		res, err := r.ReadTimeFull()
+
+		assert.NoError(t, err)
+		assert.True(t, tm.Equal(res), tm)
+		assert.Equal(t, tm.IsZero(), res.IsZero())
+		assert.Equal(t, tm.Location().String(), res.Location().String())
+		assert.Equal(t, tm.Format(time.RFC3339Nano), res.Format(time.RFC3339Nano))
+	}
+	assert.Equal(t, time.Time{}, times[0])
+}
+
+func TestReader_ReadVar_TimeFull(t *testing.T) {
+	type Event struct {
+		Name string
+		At   time.Time
+	}
+	times := []time.Time{{}, time.Date(2500, 01, 01, 0, 0, 0, 1, time.UTC), time.Date(1000, 01, 01, 0, 0, 0, 0, time.UTC)}
+	w := NewBuffer(nil)
+	w.SetMode(ModeStructs | ModeTimeFull)
+	w.WriteVar(times, Event{"x", times[1]}, "tail")
+
+	var res []time.Time
+	var ev Event
+	r := NewBuffer(w.Bytes())
+	r.SetMode(ModeStructs | ModeTimeFull)
+	err := w.ReadVar(&res, &ev)
+	tail, _ := w.ReadString()
+	err2 := Skip[[]time.Time](&r.Reader)
+	err3 := r.ReadVar(&ev)
+
+	assert.NoError(t, err)
+	assert.NoError(t, err2)
+	assert.NoError(t, err3)
+	assert.Equal(t, times, res)
+	assert.Equal(t, Event{"x", times[1]}, ev)
+	assert.Equal(t, "tail", tail)
+	assert.Len(t, Encode(times[1]), 8) // WriteTime by default
+}
+
+func TestReader_ReadTimeFull_UnknownZones(t *testing.T) {
+	w := NewBuffer(nil)
+	for i := 0; i < 10; i++ {
+		w.WriteTimeFull(time.Unix(0, 0).In(time.FixedZone(fmt.Sprint("Zone/", i), 3600)))
+	}
+	for i := 0; i < 10; i++ {
+		tm, err := w.ReadTimeFull()
+		name, off := tm.Zone()
+
+		assert.NoError(t, err)
+		assert.Equal(t, fmt.Sprint("Zone/", i), name)
+		assert.Equal(t, 3600, off)
+	}
+}
+
+func TestLocationCache_Limit(t *testing.T) {
+	c := locationCache{limit: 3}
+
+	loc1 := c.load("UTC")
+	loc2 := c.load("Zone/1")
+	loc3 := c.load("Zone/2")
+	loc4 := c.load("America/New_York") // not looked up when cache is full
+	loc5 := c.load("UTC")
+
+	assert.NotNil(t, loc1)
+	assert.Nil(t, loc2)
+	assert.Nil(t, loc3)
+	assert.Nil(t, loc4)
+	assert.Equal(t, loc1, loc5)
+	assert.Len(t, c.locs, 3)
+}
+
+func TestReader_ReadDuration(t *testing.T) {
+	w := NewBuffer(nil)
+	w.WriteVar(90*time.Minute, -time.Nanosecond)
+
+	var d1, d2 time.Duration
+	err := w.ReadVar(&d1, &d2)
+
+	assert.NoError(t, err)
+	assert.Equal(t, Encode(int64(90*time.Minute), -1), Encode(90*time.Minute, -time.Nanosecond))
+	assert.Equal(t, 90*time.Minute, d1)
+	assert.Equal(t, -time.Nanosecond, d2)
+}
+
+func TestReader_ReadTimeSec(t *testing.T) {
+	times := []time.Time{
+		time.Date(2016, 07, 06, 18, 24, 45, 0, time.UTC),
+		time.Date(1900, 01, 01, 0, 0, 0, 0, time.UTC),
+		time.Date(2200, 01, 01, 0, 0, 0, 0, time.UTC),
+		time.Date(9999, 12, 31, 23, 59, 59, 0, time.UTC),
+	}
+	w := NewBuffer(nil)
+	for _, tm := range times {
+		w.WriteTimeSec(tm)
+	}
+
+	r := w.Reader
+	for _, tm := range times {
+		res, err := r.ReadTimeSec()
+
+		assert.NoError(t, err)
+		assert.Equal(t, tm, res.UTC())
+	}
+}
+
+func TestReader_ReadDate(t *testing.T) {
+	w := NewBuffer(nil)
+	w.WriteVar(NewDate(2016, 07, 06), NewDate(1900, 01, 01))
+
+	var d1, d2 Date
+	err := w.ReadVar(&d1, &d2)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "2016-07-06", d1.String())
+	assert.Equal(t, "1900-01-01", d2.String())
+}
+
+func TestReader_ReadComplex(t *testing.T) {
+	w := NewBuffer(nil)
+	w.WriteVar(complex64(1.5-2i), complex(math.Pi, -math.E))
+
+	var c64 complex64
+	var c128 complex128
+	err := w.ReadVar(&c64, &c128)
+
+	assert.NoError(t, err)
+	assert.Equal(t, complex64(1.5-2i), c64)
+	assert.Equal(t, complex(math.Pi, -math.E), c128)
+}
+
+func TestReader_ReadBigRat(t *testing.T) {
+	x := big.NewRat(-355, 113)
+	w := NewBuffer(nil)
+	w.WriteVar(x, *big.NewRat(1, 3), (*big.Rat)(nil))
+
+	var (
+		x1 *big.Rat
+		x2 big.Rat
+		x3 = big.NewRat(5, 1)
+	)
+	err := w.ReadVar(&x1, &x2, x3)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "-355/113", x1.String())
+	assert.Equal(t, "1/3", x2.String())
+	assert.Equal(t, "0/1", x3.String())
+}
+
+func TestReader_ReadBigFloat(t *testing.T) {
+	values := []*big.Float{
+		new(big.Float),
+		new(big.Float).Neg(new(big.Float).SetPrec(100)),
+		new(big.Float).SetInf(true),
+		big.NewFloat(math.Pi),
+		big.NewFloat(-1e-300),
+		new(big.Float).SetPrec(200).SetMode(big.ToZero).Quo(big.NewFloat(1), big.NewFloat(3)),
+		new(big.Float).SetPrec(1000).SetMantExp(big.NewFloat(1.5), 100000),
+	}
+	w := NewBuffer(nil)
+	for _, x := range values {
+		w.WriteVar(x)
+	}
+
+	r := w.Reader
+	for _, x := range values {
+		var res *big.Float
+		err := r.ReadVar(&res)
+
+		assert.NoError(t, err)
+		assert.Equal(t, x.Prec(), res.Prec())
+		assert.Equal(t, x.Mode(), res.Mode())
+		assert.Equal(t, x.Signbit(), res.Signbit())
+		assert.Equal(t, x.IsInf(), res.IsInf())
+		assert.Equal(t, 0, x.Cmp(res))
+		assert.Equal(t, x.Text('p', 0), res.Text('p', 0))
+	}
+}
+
+func TestReader_ReadBigFloat_CorruptedMode(t *testing.T) {
+	w := NewBuffer(nil)
+	w.WriteByte(bigFloatFinite)
+	w.WriteVarUint64(8)                  // prec
+	w.WriteByte(200)                     // invalid rounding mode
+	w.WriteVarInt(0)                     // exp
+	w.WriteBigInt(big.NewInt(1<<20 + 1)) // mantissa wider than prec
+
+	var res *big.Float
+	err := w.ReadVar(&res)
+
+	assert.ErrorIs(t, err, errBinaryDataWasCorrupted)
+	assert.Nil(t, res)
+}
+
+func TestReader_ReadAddr(t *testing.T) {
+	addrs := []netip.Addr{
+		{},
+		netip.MustParseAddr("192.168.1.10"),
+		netip.MustParseAddr("2001:db8::1"),
+		netip.MustParseAddr("fe80::1%eth0"),
+		netip.MustParseAddr("::ffff:10.0.0.1"),
+	}
+	w := NewBuffer(nil)
+	for _, a := range addrs {
+		w.WriteVar(a)
+	}
+
+	assert.Equal(t, []byte{0, 4, 192, 168, 1, 10}, w.Bytes()[:6])
+	for _, a := range addrs {
+		var res netip.Addr
+		err := w.ReadVar(&res)
+
+		assert.NoError(t, err)
+		assert.Equal(t, a, res)
+	}
+}
+
+func TestReader_ReadPrefix(t *testing.T) {
+	p := netip.MustParsePrefix("10.1.0.0/16")
+	p6 := netip.MustParsePrefix("2001:db8::/32")
+	ap := netip.MustParseAddrPort("[2001:db8::1]:443")
+	w := NewBuffer(nil)
+	w.WriteVar(p, p6, ap, netip.Prefix{}, netip.AddrPort{})
+
+	var (
+		p1, p2, p3 netip.Prefix
+		ap1, ap2   netip.AddrPort
+	)
+	err := w.ReadVar(&p1, &p2, &ap1, &p3, &ap2)
+
+	assert.NoError(t, err)
+	assert.Equal(t, p, p1)
+	assert.Equal(t, p6, p2)
+	assert.Equal(t, ap, ap1)
+	assert.Equal(t, netip.Prefix{}, p3)
+	assert.Equal(t, netip.AddrPort{}, ap2)
+}
+
+func TestReader_ReadIP(t *testing.T) {
+	ip4 := net.ParseIP("192.168.1.10")
+	ip6 := net.ParseIP("2001:db8::1")
+	w := NewBuffer(nil)
+	w.WriteVar(ip4, ip6, net.IP(nil), net.IPv4(1, 2, 3, 4).To4())
+
+	var res1, res2, res3, res4 net.IP
+	err := w.ReadVar(&res1, &res2, &res3, &res4)
+
+	assert.NoError(t, err)
+	assert.Equal(t, ip4, res1)
+	assert.Equal(t, ip6, res2)
+	assert.Nil(t, res3)
+	assert.True(t, net.IPv4(1, 2, 3, 4).Equal(res4))
+	assert.Equal(t, 1+4, len(Encode(ip4)))
+	assert.Equal(t, 1+16, len(Encode(ip6)))
+}
+
+func TestReader_ReadAddr_Legacy(t *testing.T) {
+	addrs := []netip.Addr{{}, netip.MustParseAddr("192.168.1.10"), netip.MustParseAddr("2001:db8::1"), netip.MustParseAddr("fe80::1%eth0")}
+	prefixes := []netip.Prefix{{}, netip.MustParsePrefix("10.1.0.0/16"), netip.MustParsePrefix("2001:db8::/32")}
+	ports := []netip.AddrPort{{}, netip.MustParseAddrPort("10.0.0.1:80"), netip.MustParseAddrPort("[fe80::1%eth0]:443")}
+
+	// legacy form: values were written as encoding.BinaryMarshaler
+	w := NewBuffer(nil)
+	for _, a := range addrs {
+		b, _ := a.MarshalBinary()
+		w.WriteBytes(b)
+	}
+	for _, p := range prefixes {
+		b, _ := p.MarshalBinary()
+		w.WriteBytes(b)
+	}
+	for _, ap := range ports {
+		b, _ := ap.MarshalBinary()
+		w.WriteBytes(b)
+	}
+	w.WriteVar(addrs, prefixes, ports) // native form
+
+	for i := 0; i < 2; i++ {
+		var (
+			aa []netip.Addr
+			pp []netip.Prefix
+			ap []netip.AddrPort
+		)
+		if i == 0 {
+			aa, pp, ap = make([]netip.Addr, len(addrs)), make([]netip.Prefix, len(prefixes)), make([]netip.AddrPort, len(ports))
+			for j := range aa {
+				aa[j], _ = w.ReadAddr()
+			}
+			for j := range pp {
+				pp[j], _ = w.ReadPrefix()
+			}
+			for j := range ap {
+				ap[j], _ = w.ReadAddrPort()
+			}
+		} else {
+			w.ReadVar(&aa, &pp, &ap)
+		}
+
+		assert.NoError(t, w.Error())
+		assert.Equal(t, addrs, aa)
+		assert.Equal(t, prefixes, pp)
+		assert.Equal(t, ports, ap)
+	}
+}
+
+func TestWriter_WriteIP_InvalidLength(t *testing.T) {
+	w := NewBuffer(nil)
+
+	err := w.WriteVar(net.IP{1, 2, 3})
+
+	assert.ErrorIs(t, err, errInvalidIPLength)
+}
+
+func TestReader_ReadURL(t *testing.T) {
+	u, _ := url.Parse("https://user@example.com:8080/path?q=1#frag")
+	w := NewBuffer(nil)
+	w.WriteVar(u, *u, (*url.URL)(nil))
+
+	var (
+		u1 *url.URL
+		u2 url.URL
+		u3 = u
+	)
+	err := w.ReadVar(&u1, &u2, &u3)
+
+	assert.NoError(t, err)
+	assert.Equal(t, u, u1)
+	assert.Equal(t, *u, u2)
+	assert.Nil(t, u3)
+}
+
+func TestReader_ModeNilEmpty(t *testing.T) {
+	type Obj struct {
+		A []int
+		B map[string]int
+		C []byte
+	}
+	vals := []any{
+		[]byte(nil), []byte{}, []string(nil), []string{}, [][]byte{nil, {}},
+		[]int(nil), []int{}, map[string]int(nil), map[string]int{}, "",
+		Obj{[]int{}, map[string]int{}, nil},
+	}
+	w := NewBuffer(nil)
+	w.SetMode(ModeNilEmpty | ModeStructs)
+	w.WriteVar(vals...)
+
+	var (
+		b1, b2 = []byte{1}, []byte(nil)
+		s1, s2 = []string{""}, []string(nil)
+		bb     [][]byte
+		i1, i2 = []int{1}, []int(nil)
+		m1, m2 = map[string]int{"": 1}, map[string]int(nil)
+		str    string
+		obj    Obj
+	)
+	err := w.ReadVar(&b1, &b2, &s1, &s2, &bb, &i1, &i2, &m1, &m2, &str, &obj)
+
+	assert.NoError(t, err)
+	assert.Equal(t, vals, []any{b1, b2, s1, s2, bb, i1, i2, m1, m2, str, obj})
+}
+
+func TestReader_ModeNilEmpty_compatibility(t *testing.T) {
+	w := NewBuffer(nil)
+	w.SetMode(ModeNilEmpty)
+	w.WriteVar([]byte{}, []byte{1}, []int{}, "")
+
+	assert.Equal(t, Encode([]byte{}, []byte{1}, []int{}, ""), w.Bytes())
+
+	// nil written in ModeNilEmpty can not be read in default mode
+	w = NewBuffer(nil)
+	w.SetMode(ModeNilEmpty)
+	w.WriteVar([]int(nil))
+	var res []int
+	err := Decode(w.Bytes(), &res)
+
+	assert.Error(t, err)
+}
+
+func TestReader_ModeLEB128(t *testing.T) {
+	w := NewBuffer(nil)
+	w.SetMode(ModeLEB128)
+
+	w.WriteVar(int64(-300), uint64(300), 1, uint8(200), "abc", []uint32{1, 1 << 20})
+
+	var exp []byte
+	exp = binary.AppendVarint(exp, -300)
+	exp = binary.AppendUvarint(exp, 300)
+	exp = binary.AppendVarint(exp, 1)
+	exp = binary.AppendUvarint(exp, 200)
+	exp = append(binary.AppendUvarint(exp, 3), "abc"...)
+	exp = binary.AppendUvarint(exp, 2)
+	exp = binary.AppendUvarint(exp, 1)
+	exp = binary.AppendUvarint(exp, 1<<20)
+	assert.Equal(t, exp, w.Bytes())
+
+	var (
+		i  int64
+		u  uint64
+		n  int
+		b  uint8
+		s  string
+		uu []uint32
+	)
+	err := w.ReadVar(&i, &u, &n, &b, &s, &uu)
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(-300), i)
+	assert.Equal(t, uint64(300), u)
+	assert.Equal(t, 1, n)
+	assert.Equal(t, uint8(200), b)
+	assert.Equal(t, "abc", s)
+	assert.Equal(t, []uint32{1, 1 << 20}, uu)
+}
+
+func TestReader_ModeLEB128_nil(t *testing.T) {
+	w := NewBuffer(nil)
+	w.SetMode(ModeLEB128 | ModeNilEmpty | ModeStringTable)
+
+	w.WriteVar([]byte(nil), []byte{}, "abc", "abc", uint64(math.MaxUint64), int64(math.MinInt64))
+	var (
+		b1, b2 = []byte{1}, []byte(nil)
+		s1, s2 string
+		u      uint64
+		i      int64
+	)
+	err := w.ReadVar(&b1, &b2, &s1, &s2, &u, &i)
+
+	assert.NoError(t, err)
+	assert.Nil(t, b1)
+	assert.Equal(t, []byte{}, b2)
+	assert.Equal(t, "abc", s1)
+	assert.Equal(t, "abc", s2)
+	assert.Equal(t, uint64(math.MaxUint64), u)
+	assert.Equal(t, int64(math.MinInt64), i)
+}
+
+func TestReader_ModeLEB128_corrupted(t *testing.T) {
+	r := NewBuffer(bytes.Repeat([]byte{0xff}, 11))
+	r.SetMode(ModeLEB128)
+
+	_, err := r.ReadVarUint64()
+
+	assert.Error(t, err)
+}
+
 //-----------------------------------
 type Point struct {
 	X int
@@ -254,3 +742,49 @@ func (u *User) Encode() []byte {
 func (u *User) Decode(data []byte) error {
 	return Decode(data, &u.ID, &u.Name)
 }
+
+func TestReader_ReadBytes_Large(t *testing.T) {
+	data := bytes.Repeat([]byte("0123456789"), 100000)
+
+	var res []byte
+	err := Decode(Encode(data), &res)
+	var s string
+	err2 := Decode(Encode(1<<40, "abc"), &s)
+	var res3 []byte
+	err3 := Decode(Encode(math.MaxInt64), &res3)
+
+	assert.NoError(t, err)
+	assert.Equal(t, data, res)
+	assert.Error(t, err2)
+	assert.Error(t, err3)
+}
+
+func TestReader_ReadPrefix_Invalid(t *testing.T) {
+	p := netip.PrefixFrom(netip.MustParseAddr("1.2.3.4"), 40)
+	w := NewBuffer(nil)
+	w.WriteVar(p, "tail")
+
+	var p1 netip.Prefix
+	var s string
+	err := w.ReadVar(&p1, &s)
+
+	assert.NoError(t, err)
+	assert.Equal(t, netip.Prefix{}, p1)
+	assert.Equal(t, "tail", s)
+	assert.Equal(t, []byte{addrNone}, Encode(p))
+}
+
+func TestReader_ReadAddr_LegacyCorruptedLength(t *testing.T) {
+	for _, n := range []int64{maxLegacyAddrLen + 1, 1 << 40, math.MaxInt64} {
+		var a netip.Addr
+		var p netip.Prefix
+		var ap netip.AddrPort
+		err := Decode(Encode(n), &a)
+		err2 := Decode(Encode(n), &p)
+		err3 := Decode(Encode(n), &ap)
+
+		assert.ErrorIs(t, err, errBinaryDataWasCorrupted, n)
+		assert.ErrorIs(t, err2, errBinaryDataWasCorrupted, n)
+		assert.ErrorIs(t, err3, errBinaryDataWasCorrupted, n)
+	}
+}
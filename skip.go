@@ -0,0 +1,286 @@
+package bin
+
+import (
+	"encoding"
+	"errors"
+	"io"
+	"math/big"
+	"net"
+	"net/netip"
+	"net/url"
+	"reflect"
+	"time"
+)
+
+// SkipVar advances past the next value of the given type (value written by WriteVar).
+// Length-prefixed payloads are discarded without allocation. Values of types with
+// custom decoding (BinRead, BinaryDecode, gob, ...) are decoded and discarded.
+// In self-describing formats (FormatMsgPack, FormatCBOR) the next data item is skipped regardless of type.
+func (r *Reader) SkipVar(t reflect.Type) error {
+	if r.err != nil {
+		return r.err
+	}
+	switch r.format {
+	case FormatMsgPack:
+		return r.skipMsgPack()
+	case FormatCBOR:
+		return r.skipCBOR()
+	}
+	if t == nil {
+		r.SetError(errSkipNilType)
+		return r.err
+	}
+	return r.skipVar(t)
+}
+
+// Skip advances past the next value of type T
+func Skip[T any](r *Reader) error {
+	return r.SkipVar(reflect.TypeOf((*T)(nil)).Elem())
+}
+
+var errSkipNilType = errors.New("bin.SkipVar-Error: type of value is required in FormatBin")
+
+var (
+	typeInterfaceBinaryDecoder = reflect.TypeOf((*binaryDecoder)(nil)).Elem()
+	typeInterfaceDecoder       = reflect.TypeOf((*Decoder)(nil)).Elem()
+	typeInterfaceBinReader     = reflect.TypeOf((*binReader)(nil)).Elem()
+	typeInterfaceUnmarshaler   = reflect.TypeOf((*encoding.BinaryUnmarshaler)(nil)).Elem()
+	typeError                  = reflect.TypeOf((*error)(nil)).Elem()
+)
+
+// decodedTypes are types decoded by readVar with their own methods (they are skipped by decoding)
+var decodedTypes = map[reflect.Type]bool{
+	reflect.TypeOf(big.Int{}):         true,
+	reflect.TypeOf((*big.Int)(nil)):   true,
+	reflect.TypeOf(big.Rat{}):         true,
+	reflect.TypeOf((*big.Rat)(nil)):   true,
+	reflect.TypeOf(big.Float{}):       true,
+	reflect.TypeOf((*big.Float)(nil)): true,
+	reflect.TypeOf(netip.Addr{}):      true,
+	reflect.TypeOf(netip.Prefix{}):    true,
+	reflect.TypeOf(netip.AddrPort{}):  true,
+	reflect.TypeOf(net.IP{}):          true,
+	reflect.TypeOf(url.URL{}):         true,
+	reflect.TypeOf((*url.URL)(nil)):   true,
+	reflect.TypeOf(Uint128{}):         true,
+	reflect.TypeOf(Uint256{}):         true,
+}
+
+// skipVar mirrors type dispatch of readVar in native format
+func (r *Reader) skipVar(t reflect.Type) error {
+	switch t {
+	case reflect.TypeOf(0), reflect.TypeOf(int8(0)), reflect.TypeOf(int16(0)), reflect.TypeOf(int32(0)), reflect.TypeOf(int64(0)),
+		reflect.TypeOf(uint(0)), reflect.TypeOf(uint8(0)), reflect.TypeOf(uint16(0)), reflect.TypeOf(uint32(0)), reflect.TypeOf(uint64(0)),
+		reflect.TypeOf(time.Duration(0)), reflect.TypeOf(Date(0)):
+		return r.skipVarInt()
+	case reflect.TypeOf(false):
+		return r.discard(1)
+	case reflect.TypeOf(Float16(0)), reflect.TypeOf(BFloat16(0)):
+		return r.discard(2)
+	case reflect.TypeOf(float32(0)):
+		return r.discard(4)
+	case reflect.TypeOf(float64(0)), reflect.TypeOf(complex64(0)):
+		return r.discard(8)
+	case typeTime:
+		if r.mode&ModeTimeFull != 0 {
+			return r.skipTime()
+		}
+		return r.discard(8)
+	case reflect.TypeOf(complex128(0)):
+		return r.discard(16)
+	case reflect.TypeOf(""), typeError:
+		return r.skipString()
+	case reflect.TypeOf([]byte{}), reflect.TypeOf(Bytes{}):
+		return r.skipBytes()
+	case typeRawValue:
+		r.SetError(errRawValueType)
+		return r.err
+	case reflect.TypeOf([]string{}):
+		return r.skipItems(r.skipString)
+	case reflect.TypeOf([][]byte{}):
+		return r.skipItems(r.skipBytes)
+	case reflect.TypeOf([]Float16{}), reflect.TypeOf([]BFloat16{}):
+		n, err := r.readLen()
+		if err != nil {
+			return err
+		}
+		return r.discard(2 * n)
+	case reflect.TypeOf(DeltaInts{}), reflect.TypeOf(DeltaUints{}), reflect.TypeOf(Delta2Ints{}):
+		return r.skipItems(r.skipVarInt)
+	case reflect.TypeOf(DeltaTimes{}), reflect.TypeOf(Delta2Times{}):
+		return r.skipDeltaTimes()
+	}
+	if t.Implements(typeTextEncoder) {
+		return r.skipBytes()
+	}
+	if decodedTypes[t] {
+		return r.skipByDecoding(t)
+	}
+	switch pt := reflect.PointerTo(t); {
+	case pt.Implements(typeInterfaceBinaryDecoder):
+		return r.skipByDecoding(t)
+	case pt.Implements(typeInterfaceDecoder):
+		return r.skipBytes()
+	case pt.Implements(typeInterfaceBinReader):
+		return r.skipByDecoding(t)
+	case pt.Implements(typeInterfaceUnmarshaler):
+		return r.skipBytes()
+	}
+
+	switch t.Kind() {
+	case reflect.Ptr:
+		if r.mode&ModeStructs != 0 && isPlainStruct(t.Elem()) {
+			if ok, err := r.ReadBool(); err != nil || !ok {
+				return r.err
+			}
+			return r.skipVar(t.Elem())
+		}
+		if reflect.PointerTo(t.Elem()).Implements(typeInterfaceDecoder) {
+			return r.skipBytes()
+		}
+
+	case reflect.Map:
+		return r.skipItems(func() error {
+			if r.skipVar(t.Key()) != nil {
+				return r.err
+			}
+			return r.skipVar(t.Elem())
+		})
+
+	case reflect.Slice:
+		return r.skipItems(func() error { return r.skipVar(t.Elem()) })
+
+	case reflect.Struct:
+		if r.mode&ModeStructs != 0 {
+			info := getStructInfo(t)
+			if err := info.check(t, r.mode); err != nil {
+				r.SetError(err)
+				return err
+			}
+			for i := 0; i < len(info.fields); i++ {
+				f := info.fields[i]
+				if f.ptr {
+					if ok, err := r.ReadBool(); err != nil {
+						break
+					} else if !ok {
+						i += f.n
+					}
+					continue
+				}
+				ft := f.typ
+				if f.opts != "" {
+					ft = reflect.TypeOf(withDeltaOpts(reflect.New(ft).Interface(), f.opts)).Elem()
+				}
+				if r.skipVar(ft) != nil {
+					break
+				}
+			}
+			return r.err
+		}
+	}
+	return r.skipByDecoding(t)
+}
+
+// skipByDecoding reads value of type and discards it
+func (r *Reader) skipByDecoding(t reflect.Type) error {
+	return r.readVar(reflect.New(t).Interface())
+}
+
+// discard skips n bytes
+func (r *Reader) discard(n int) error {
+	if n > 0 {
+		io.CopyN(io.Discard, r, int64(n))
+	}
+	return r.err
+}
+
+// skipTime skips time written by Writer.WriteTimeFull
+func (r *Reader) skipTime() error {
+	if kind, err := r.ReadByte(); err != nil || kind == timeZero || r.skipLocation(kind) != nil {
+		return r.err
+	}
+	r.skipVarInt()
+	return r.skipVarInt()
+}
+
+// skipLocation skips location written by Writer.writeLocation
+func (r *Reader) skipLocation(kind byte) error {
+	switch kind {
+	case timeUTC, timeLocal:
+	case timeZone:
+		r.skipVarInt()
+		r.skipString()
+	default:
+		r.SetError(errBinaryDataWasCorrupted)
+	}
+	return r.err
+}
+
+// skipDeltaTimes skips times written by Writer.writeDeltaTimes
+func (r *Reader) skipDeltaTimes() error {
+	n, err := r.readLen()
+	if err != nil || n <= 0 {
+		return err
+	}
+	if kind, err := r.ReadByte(); err != nil || r.skipLocation(kind) != nil {
+		return r.err
+	}
+	for i := 0; i < 2*n && r.err == nil; i++ {
+		r.skipVarInt()
+	}
+	return r.err
+}
+
+func (r *Reader) skipVarInt() error {
+	b, err := r.ReadByte()
+	if err != nil {
+		return err
+	}
+	if r.mode&ModeLEB128 != 0 {
+		for i := 1; b >= 0x80 && err == nil; i++ {
+			if i == 10 {
+				r.SetError(errBinaryDataWasCorrupted)
+				return r.err
+			}
+			b, err = r.ReadByte()
+		}
+		return err
+	}
+	if b&0x80 == 0 {
+		return nil
+	}
+	if n := int(b & 0x3f); n <= 8 {
+		return r.discard(n)
+	}
+	r.SetError(errBinaryDataWasCorrupted)
+	return r.err
+}
+
+func (r *Reader) skipBytes() error {
+	n, err := r.readLen()
+	if err != nil {
+		return err
+	}
+	return r.discard(n)
+}
+
+func (r *Reader) skipString() error {
+	if r.mode&ModeStringTable != 0 {
+		_, err := r.readStringRef() // inline strings are added to the table
+		return err
+	}
+	n, err := r.readVarLen()
+	if err != nil {
+		return err
+	}
+	return r.discard(n)
+}
+
+// skipItems reads length of slice or map and skips its items
+func (r *Reader) skipItems(skip func() error) error {
+	n, err := r.readLen()
+	for i := 0; i < n && err == nil; i++ {
+		err = skip()
+	}
+	return r.err
+}
@@ -0,0 +1,186 @@
+package bin
+
+import (
+	"bytes"
+	"math/big"
+	"net/netip"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReader_SkipVar(t *testing.T) {
+	values := []any{
+		-1000, uint64(1 << 40), int8(-1), true, 1.5, float32(2.5), complex(1, 2), NewFloat16(1),
+		time.Unix(1, 0), time.Hour, Date(100), "abc", []byte{1, 2, 3}, Bytes(nil), []string{"a", "b"},
+		[][]byte{{1}, nil}, []Float16{1, 2}, DeltaInts{1, 2, 3}, big.NewInt(-1e18), *big.NewRat(1, 3),
+		netip.MustParseAddr("::1"), Uint128{1, 2}, HashH256("abc"), &User{1, "user"}, Some(5),
+		[]int{1, 2, 3}, map[string][]uint32{"a": {1}}, Point{1, 2},
+	}
+	for _, v := range values {
+		data := Encode(v, "tail")
+
+		r := NewBuffer(data)
+		err := r.SkipVar(reflect.TypeOf(v))
+		tail, _ := r.ReadString()
+
+		assert.NoError(t, err, v)
+		assert.Equal(t, "tail", tail, v)
+	}
+}
+
+func TestReader_SkipVar_modes(t *testing.T) {
+	type Rec struct {
+		ID    int
+		Name  string
+		Point *Point
+		Data  []byte
+		IDs   []int64 `bin:",delta"`
+		Items map[string]float64
+	}
+	rec := Rec{1, "name", &Point{1, 2}, []byte{1, 2, 3}, []int64{10, 11}, map[string]float64{"x": 1}}
+
+	for _, mode := range []Mode{ModeStructs, ModeStructs | ModeNilEmpty | ModeLEB128, ModeStructs | ModeStringTable} {
+		w := NewBuffer(nil)
+		w.SetMode(mode)
+		w.WriteVar("name", rec, "name", []*Point{nil, {3, 4}})
+
+		err := w.SkipVar(reflect.TypeOf(""))
+		err2 := Skip[Rec](&w.Reader)
+		var s string
+		var pp []*Point
+		err3 := w.ReadVar(&s, &pp)
+
+		assert.NoError(t, err)
+		assert.NoError(t, err2)
+		assert.NoError(t, err3)
+		assert.Equal(t, "name", s)
+		assert.Equal(t, []*Point{nil, {3, 4}}, pp)
+	}
+}
+
+func TestReader_SkipVar_selfDescribing(t *testing.T) {
+	values := []any{
+		1, -1000, uint64(1 << 40), 1.5, "abc", string(make([]byte, 300)), []byte{1, 2}, nil, true,
+		time.Unix(1, 1), big.NewInt(-1), []any{1, []any{2, "x"}, map[string]int{"a": 1}},
+		map[int]string{1: "a", 2: "b"}, Point{1, 2}, &User{1, "user"},
+	}
+	for _, format := range []Format{FormatMsgPack, FormatCBOR} {
+		for _, v := range values {
+			w := NewBuffer(nil)
+			w.SetFormat(format)
+			w.WriteVar(v, "tail")
+
+			err := w.SkipVar(nil)
+			var tail string
+			err2 := w.ReadVar(&tail)
+
+			assert.NoError(t, err, v)
+			assert.NoError(t, err2, v)
+			assert.Equal(t, "tail", tail, v)
+		}
+	}
+
+	// indefinite-length CBOR items
+	r := NewBuffer([]byte{0x9f, 0x5f, 0x41, 1, 0xff, 0xbf, 0x61, 'a', 0x01, 0xff, 0xff, 0x01})
+	r.SetFormat(FormatCBOR)
+	err := r.SkipVar(nil)
+	var n int
+	r.ReadVar(&n)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, n)
+}
+
+func TestReader_SkipVar_discard(t *testing.T) {
+	data := Encode(make([]byte, 1<<20), 1)
+
+	allocs := testing.AllocsPerRun(10, func() {
+		r := NewReader(bytes.NewReader(data))
+		r.SkipVar(reflect.TypeOf([]byte{}))
+	})
+	r := NewReader(bytes.NewReader(data))
+	err := Skip[[]byte](r)
+	n, _ := r.ReadVarInt()
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, n)
+	assert.Less(t, allocs, 10.0)
+}
+
+func TestReader_SkipVar_errors(t *testing.T) {
+	r := NewBuffer(Encode(100)) // length of 100 bytes without payload
+	err := Skip[[]byte](&r.Reader)
+
+	r2 := NewBuffer([]byte{0xc1})
+	r2.SetFormat(FormatMsgPack)
+	err2 := r2.SkipVar(nil)
+
+	r3 := NewBuffer([]byte{0xbf, 0x01})
+	r3.SetFormat(FormatCBOR)
+	err3 := r3.SkipVar(nil)
+
+	assert.Error(t, err)
+	assert.Error(t, err2)
+	assert.Error(t, err3)
+}
+
+func TestReader_SkipVar_nilType(t *testing.T) {
+	r := NewBuffer(Encode(1))
+
+	err := r.SkipVar(nil)
+
+	assert.ErrorIs(t, err, errSkipNilType)
+}
+
+func TestReader_SkipVar_deepNesting(t *testing.T) {
+	for _, tc := range []struct {
+		format Format
+		nested byte // one-item array
+		err    error
+	}{
+		{FormatMsgPack, 0x91, errMsgPackCorrupted},
+		{FormatCBOR, 0x81, errCBORCorrupted},
+	} {
+		data := bytes.Repeat([]byte{tc.nested}, 1e6)
+
+		r1 := NewBuffer(data)
+		r1.SetFormat(tc.format)
+		var v any
+		err1 := r1.ReadVar(&v)
+
+		r2 := NewBuffer(data)
+		r2.SetFormat(tc.format)
+		err2 := r2.SkipVar(nil)
+
+		assert.ErrorIs(t, err1, tc.err)
+		assert.ErrorIs(t, err2, tc.err)
+	}
+}
+
+func TestReader_SkipVar_nesting(t *testing.T) {
+	for _, tc := range []struct {
+		format Format
+		nested byte
+	}{
+		{FormatMsgPack, 0x91},
+		{FormatCBOR, 0x81},
+	} {
+		data := append(bytes.Repeat([]byte{tc.nested}, maxNestingDepth-1), 0x01)
+
+		r1 := NewBuffer(data)
+		r1.SetFormat(tc.format)
+		var v any
+		err1 := r1.ReadVar(&v)
+
+		r2 := NewBuffer(data)
+		r2.SetFormat(tc.format)
+		err2 := r2.SkipVar(nil)
+
+		assert.NoError(t, err1)
+		assert.NoError(t, err2)
+		assert.Equal(t, 0, r2.Reader.depth)
+	}
+}
@@ -0,0 +1,66 @@
+package bin
+
+// String in ModeStringTable is written as varint header h:
+//   h&1 == 0 - inline string of length h>>1 (added to the table if non-empty)
+//   h&1 == 1 - reference to string with index h>>1 in the table
+
+// ResetStringTable clears the string table of writer
+func (w *Writer) ResetStringTable() {
+	w.strs = nil
+}
+
+// ResetStringTable clears the string table of reader
+func (r *Reader) ResetStringTable() {
+	r.strs = nil
+}
+
+// ResetStringTable clears the string tables of both reader and writer of buffer
+func (b *Buffer) ResetStringTable() {
+	b.Writer.ResetStringTable()
+	b.Reader.ResetStringTable()
+}
+
+func (w *Writer) writeStringRef(s string) error {
+	if i, ok := w.strs[s]; ok {
+		return w.WriteVarUint64(uint64(i)<<1 | 1)
+	}
+	if w.WriteVarUint64(uint64(len(s))<<1) != nil || s == "" {
+		return w.err
+	}
+	if _, err := w.Write([]byte(s)); err == nil {
+		if w.strs == nil {
+			w.strs = map[string]int{}
+		}
+		w.strs[s] = len(w.strs)
+	}
+	return w.err
+}
+
+func (r *Reader) readStringRef() (string, error) {
+	h, err := r.ReadVarUint64()
+	if err != nil {
+		return "", err
+	}
+	if h&1 == 1 {
+		if i := h >> 1; i < uint64(len(r.strs)) {
+			return r.strs[i], nil
+		}
+		r.SetError(errBinaryDataWasCorrupted)
+		return "", r.err
+	}
+	n := int(h >> 1)
+	if n == 0 {
+		return "", nil
+	}
+	if n < 0 {
+		r.SetError(errBinaryDataWasCorrupted)
+		return "", r.err
+	}
+	b, err := r.read(n)
+	if err != nil {
+		return "", err
+	}
+	s := string(b)
+	r.strs = append(r.strs, s)
+	return s, nil
+}
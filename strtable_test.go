@@ -0,0 +1,63 @@
+package bin
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestModeStringTable(t *testing.T) {
+	type Event struct {
+		Name string
+		Host string
+	}
+	events := []Event{{"start", "host1"}, {"stop", "host1"}, {"start", "host2"}, {"start", ""}}
+	w := NewBuffer(nil)
+	w.SetMode(ModeStringTable | ModeStructs)
+
+	w.WriteVar(events, map[string]string{"host1": "stop"}, "start")
+	size := len(w.Bytes())
+	var (
+		res []Event
+		mp  map[string]string
+		s   string
+	)
+	err := w.ReadVar(&res, &mp, &s)
+
+	assert.NoError(t, err)
+	assert.Equal(t, events, res)
+	assert.Equal(t, map[string]string{"host1": "stop"}, mp)
+	assert.Equal(t, "start", s)
+	assert.Equal(t, 32, size) // 4 inline strings, 6 references, 2 lengths and empty string
+}
+
+func TestModeStringTable_reset(t *testing.T) {
+	w := NewBuffer(nil)
+	w.SetMode(ModeStringTable)
+
+	w.WriteString("abc")
+	w.WriteString("abc")
+	w.ResetStringTable()
+	w.WriteString("abc")
+
+	assert.Equal(t, []byte{6, 'a', 'b', 'c', 1, 6, 'a', 'b', 'c'}, w.Bytes())
+
+	s1, _ := w.ReadString()
+	s2, _ := w.ReadString()
+	w.ResetStringTable()
+	s3, err := w.ReadString()
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"abc", "abc", "abc"}, []string{s1, s2, s3})
+}
+
+func TestModeStringTable_corrupted(t *testing.T) {
+	r := NewBuffer([]byte{6, 'a', 'b', 'c', 3})
+	r.SetMode(ModeStringTable)
+
+	_, err1 := r.ReadString()
+	_, err2 := r.ReadString()
+
+	assert.NoError(t, err1)
+	assert.Error(t, err2)
+}
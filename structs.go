@@ -0,0 +1,268 @@
+package bin
+
+import (
+	"encoding"
+	"fmt"
+	"math/big"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+)
+
+type structField struct {
+	name  string // dot-separated path of field
+	key   string // name of field in self-describing formats
+	index []int
+	typ   reflect.Type
+	tag   reflect.StructTag
+	opts  string // options of `bin` tag
+	byPtr bool   // type is encoded by methods with pointer receiver
+	ptr   bool   // embedded pointer to struct (encoded as presence flag), its n fields follow
+	n     int
+}
+
+// StructField is a field of struct as it is seen by struct codec (see ModeStructs)
+type StructField struct {
+	Name  string // name of field (dot-separated path for fields of embedded structs)
+	Index []int  // index sequence for reflect.Value.FieldByIndex
+	Type  reflect.Type
+	Tag   reflect.StructTag
+}
+
+type structInfo struct {
+	fields     []structField
+	unexported []string // names of skipped unexported fields
+}
+
+var structInfos sync.Map // reflect.Type -> *structInfo
+
+var codecInterfaces = []reflect.Type{
+	reflect.TypeOf((*Encoder)(nil)).Elem(),
+	reflect.TypeOf((*Decoder)(nil)).Elem(),
+	reflect.TypeOf((*binaryEncoder)(nil)).Elem(),
+	reflect.TypeOf((*binaryDecoder)(nil)).Elem(),
+	reflect.TypeOf((*binWriter)(nil)).Elem(),
+	reflect.TypeOf((*binReader)(nil)).Elem(),
+	reflect.TypeOf((*encoding.BinaryMarshaler)(nil)).Elem(),
+	reflect.TypeOf((*encoding.BinaryUnmarshaler)(nil)).Elem(),
+}
+
+func getStructInfo(t reflect.Type) *structInfo {
+	if info, ok := structInfos.Load(t); ok {
+		return info.(*structInfo)
+	}
+	info := &structInfo{}
+	info.collect(t, nil, "", map[reflect.Type]bool{t: true})
+	info.resolve()
+	structInfos.Store(t, info)
+	return info
+}
+
+// collect adds fields of struct type. Embedded structs and pointers to structs are flattened
+// the way encoding/json does it (unexported embedded pointers are skipped as unexported fields).
+func (info *structInfo) collect(t reflect.Type, index []int, prefix string, embedded map[reflect.Type]bool) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("bin")
+		if tag == "-" {
+			continue
+		}
+		idx := append(append([]int{}, index...), i)
+		name := prefix + f.Name
+		if f.Anonymous && isPlainStruct(f.Type) {
+			if !embedded[f.Type] {
+				embedded[f.Type] = true
+				info.collect(f.Type, idx, name+".", embedded)
+				delete(embedded, f.Type)
+			}
+			continue
+		}
+		if f.Anonymous && f.IsExported() && f.Type.Kind() == reflect.Ptr && isPlainStruct(f.Type.Elem()) {
+			if et := f.Type.Elem(); !embedded[et] {
+				embedded[et] = true
+				info.fields = append(info.fields, structField{name: name, index: idx, typ: f.Type, ptr: true})
+				info.collect(et, idx, name+".", embedded)
+				delete(embedded, et)
+			}
+			continue
+		}
+		if !f.IsExported() {
+			info.unexported = append(info.unexported, name)
+			continue
+		}
+		_, opts, _ := strings.Cut(tag, ",")
+		info.fields = append(info.fields, structField{name, f.Name, idx, f.Type, f.Tag, opts, hasPtrEncoder(f.Type), false, 0})
+	}
+}
+
+// resolve removes fields hidden by the rules of encoding/json: of fields with the same name
+// the least nested one is encoded; if there are several of them, none is encoded.
+func (info *structInfo) resolve() {
+	depth := map[string]int{}
+	count := map[string]int{}
+	for _, f := range info.fields {
+		if d, ok := depth[f.key]; !f.ptr && (!ok || len(f.index) < d) {
+			depth[f.key], count[f.key] = len(f.index), 1
+		} else if !f.ptr && len(f.index) == d {
+			count[f.key]++
+		}
+	}
+	fields := info.fields[:0]
+	for _, f := range info.fields {
+		if f.ptr || len(f.index) == depth[f.key] && count[f.key] == 1 {
+			fields = append(fields, f)
+		}
+	}
+	for i := range fields {
+		if fields[i].ptr {
+			for j := i + 1; j < len(fields) && isPrefix(fields[i].index, fields[j].index); j++ {
+				fields[i].n++
+			}
+		}
+	}
+	info.fields = fields
+}
+
+func isPrefix(prefix, index []int) bool {
+	return len(prefix) < len(index) && slices.Equal(prefix, index[:len(prefix)])
+}
+
+// fieldByIndex returns nested field of struct v; nil embedded pointers are allocated.
+func fieldByIndex(v reflect.Value, index []int) reflect.Value {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v
+}
+
+// StructFields returns encoded fields of struct type in order of encoding.
+// Fields of embedded structs are flattened with the same rules as in encoding/json,
+// unexported fields and fields with tag `bin:"-"` are skipped.
+// Index of field of embedded pointer to struct passes through the pointer (see reflect.Value.FieldByIndexErr).
+func StructFields(t reflect.Type) []StructField {
+	var res []StructField
+	for _, f := range getStructInfo(t).fields {
+		if !f.ptr {
+			res = append(res, StructField{f.name, f.index, f.typ, f.tag})
+		}
+	}
+	return res
+}
+
+// isPlainStruct returns true if values of type are encoded by struct codec in ModeStructs
+func isPlainStruct(t reflect.Type) bool {
+	return t.Kind() == reflect.Struct && !hasOwnEncoding(t)
+}
+
+// hasOwnEncoding returns true if values of type are encoded by its own methods or natively by Writer
+func hasOwnEncoding(t reflect.Type) bool {
+	switch t {
+	case reflect.TypeOf(big.Int{}), reflect.TypeOf(big.Rat{}), reflect.TypeOf(big.Float{}):
+		return true
+	}
+	pt := reflect.PointerTo(t)
+	for _, it := range codecInterfaces {
+		if t.Implements(it) || pt.Implements(it) {
+			return true
+		}
+	}
+	return false
+}
+
+var encoderInterfaces = []reflect.Type{
+	reflect.TypeOf((*Encoder)(nil)).Elem(),
+	reflect.TypeOf((*binaryEncoder)(nil)).Elem(),
+	reflect.TypeOf((*binWriter)(nil)).Elem(),
+	reflect.TypeOf((*encoding.BinaryMarshaler)(nil)).Elem(),
+}
+
+// hasPtrEncoder returns true if values of type are encoded only by methods with pointer receiver.
+// Such values are written through pointer to be decoded by the same methods.
+func hasPtrEncoder(t reflect.Type) bool {
+	if t.Kind() == reflect.Ptr || t.Kind() == reflect.Interface {
+		return false
+	}
+	res := false
+	for _, it := range encoderInterfaces {
+		if t.Implements(it) {
+			return false
+		}
+		res = res || reflect.PointerTo(t).Implements(it)
+	}
+	return res
+}
+
+func (info *structInfo) check(t reflect.Type, mode Mode) error {
+	if mode&ModeStrictStructs == ModeStrictStructs && len(info.unexported) > 0 {
+		return fmt.Errorf("bin.Struct-Error: unexported field %s.%s", t, info.unexported[0])
+	}
+	return nil
+}
+
+func (w *Writer) writeStruct(rv reflect.Value) {
+	info := getStructInfo(rv.Type())
+	if err := info.check(rv.Type(), w.mode); err != nil {
+		w.SetError(err)
+		return
+	}
+	for i := 0; i < len(info.fields); i++ {
+		f := info.fields[i]
+		fv := rv.FieldByIndex(f.index)
+		if f.ptr {
+			if w.WriteBool(!fv.IsNil()) != nil {
+				return
+			}
+			if fv.IsNil() {
+				i += f.n
+			}
+			continue
+		}
+		v := fv.Interface()
+		if f.byPtr && fv.CanAddr() {
+			v = fv.Addr().Interface()
+		}
+		if f.opts != "" {
+			v = withDeltaOpts(v, f.opts)
+		}
+		if w.writeVar(v) != nil {
+			return
+		}
+	}
+}
+
+func (r *Reader) readStruct(p reflect.Value) {
+	info := getStructInfo(p.Type())
+	if err := info.check(p.Type(), r.mode); err != nil {
+		r.SetError(err)
+		return
+	}
+	for i := 0; i < len(info.fields); i++ {
+		f := info.fields[i]
+		fv := p.FieldByIndex(f.index)
+		if f.ptr {
+			if ok, err := r.ReadBool(); err != nil {
+				return
+			} else if !ok {
+				fv.SetZero()
+				i += f.n
+			} else if fv.IsNil() {
+				fv.Set(reflect.New(f.typ.Elem()))
+			}
+			continue
+		}
+		v := fv.Addr().Interface()
+		if f.opts != "" {
+			v = withDeltaOpts(v, f.opts)
+		}
+		if r.readVar(v) != nil {
+			return
+		}
+	}
+}
@@ -0,0 +1,267 @@
+package bin
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testHeader struct {
+	ID        uint64
+	CreatedAt time.Time
+}
+
+type Meta struct {
+	Tags []string
+}
+
+type testDoc struct {
+	testHeader
+	*Point
+	Meta
+	Title   string
+	Parts   []testPart
+	private int
+	Ignored string `bin:"-"`
+}
+
+type testPart struct {
+	Name string
+	Size int
+}
+
+func TestWriter_WriteStruct(t *testing.T) {
+	doc := testDoc{
+		testHeader: testHeader{1, time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)},
+		Meta:       Meta{[]string{"a", "b"}},
+		Title:      "Doc",
+		Parts:      []testPart{{"x", 10}, {"y", 20}},
+		private:    123,
+		Ignored:    "abc",
+	}
+	w := NewBuffer(nil)
+	w.SetMode(ModeStructs)
+
+	w.WriteVar(doc)
+	data := append([]byte{}, w.Bytes()...)
+	var res testDoc
+	err := w.ReadVar(&res)
+
+	assert.NoError(t, err)
+	assert.Equal(t, Encode(doc.ID, doc.CreatedAt, false, doc.Tags, doc.Title, 2, "x", 10, "y", 20), data)
+	assert.True(t, doc.CreatedAt.Equal(res.CreatedAt))
+	doc.private, doc.Ignored, doc.CreatedAt = 0, "", res.CreatedAt
+	assert.Equal(t, doc, res)
+}
+
+func TestWriter_WriteStruct_Pointer(t *testing.T) {
+	doc := &testDoc{Point: &Point{1, 2}, Title: "Doc"}
+	w := NewBuffer(nil)
+	w.SetMode(ModeStructs)
+
+	w.WriteVar(doc, (*testDoc)(nil))
+	var res, res2 *testDoc
+	res2 = &testDoc{}
+	err := w.ReadVar(&res, &res2)
+
+	assert.NoError(t, err)
+	assert.Equal(t, doc.Point, res.Point)
+	assert.Equal(t, doc.Title, res.Title)
+	assert.Nil(t, res2)
+}
+
+func TestWriter_WriteStruct_Strict(t *testing.T) {
+	w := NewBuffer(nil)
+	w.SetMode(ModeStrictStructs)
+
+	err := w.WriteVar(testDoc{})
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "bin.testDoc.private")
+}
+
+func TestReader_ReadStruct_Strict(t *testing.T) {
+	data := Encode(uint64(0), time.Time{}, false, []string{}, "", 0)
+
+	r := NewBuffer(data)
+	r.SetMode(ModeStrictStructs)
+	err := r.ReadVar(&testDoc{})
+
+	assert.Error(t, err)
+}
+
+func TestWriter_WriteStruct_StrictIgnored(t *testing.T) {
+	type obj struct {
+		A     int
+		b     int `bin:"-"`
+		Inner struct{ C, D string }
+	}
+	w := NewBuffer(nil)
+	w.SetMode(ModeStrictStructs)
+
+	w.WriteVar(obj{1, 2, struct{ C, D string }{"c", "d"}})
+	data := append([]byte{}, w.Bytes()...)
+	var res obj
+	err := w.ReadVar(&res)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{1, 1, 'c', 1, 'd'}, data)
+	assert.Equal(t, obj{1, 0, struct{ C, D string }{"c", "d"}}, res)
+}
+
+func TestWriter_WriteStruct_GobByDefault(t *testing.T) {
+	p := Point{1, 2}
+
+	data := Encode(p)
+	var res Point
+	err := Decode(data, &res)
+
+	assert.NoError(t, err)
+	assert.NotEqual(t, []byte{1, 2}, data)
+	assert.Equal(t, p, res)
+}
+
+func TestStructFields(t *testing.T) {
+	ff := StructFields(reflect.TypeOf(testDoc{}))
+
+	var names []string
+	for _, f := range ff {
+		names = append(names, f.Name)
+	}
+	assert.Equal(t, []string{"testHeader.ID", "testHeader.CreatedAt", "Point.X", "Point.Y", "Meta.Tags", "Title", "Parts"}, names)
+	assert.Equal(t, []int{1, 0}, ff[2].Index)
+	assert.Equal(t, []int{0, 1}, ff[1].Index)
+	assert.Equal(t, reflect.TypeOf(time.Time{}), ff[1].Type)
+}
+
+func TestWriter_WriteStruct_EmbeddedPointer(t *testing.T) {
+	type Inner struct {
+		*Point
+		Z int
+	}
+	type Outer struct {
+		*Inner
+		Name string
+	}
+	values := []Outer{{nil, "a"}, {&Inner{nil, 3}, "b"}, {&Inner{&Point{1, 2}, 3}, "c"}}
+
+	for _, v := range values {
+		for _, format := range []Format{FormatBin, FormatMsgPack, FormatCBOR} {
+			w := NewBuffer(nil)
+			w.SetMode(ModeStructs)
+			w.SetFormat(format)
+			w.WriteVar(v, "tail")
+
+			var res Outer
+			var tail string
+			err := w.ReadVar(&res, &tail)
+
+			assert.NoError(t, err, format)
+			assert.Equal(t, v, res, format)
+			assert.Equal(t, "tail", tail, format)
+		}
+	}
+	// embedded pointer is written as presence flag and flattened fields
+	assert.Equal(t, Encode(true, true, 1, 2, 3, "c"), encodeStructs(values[2]))
+	assert.Equal(t, Encode(true, false, 3, "b"), encodeStructs(values[1]))
+	assert.Equal(t, map[string]any{"X": int64(1), "Y": int64(2), "Z": int64(3), "Name": "c"}, decodeMsgPackAny(t, values[2]))
+	assert.Equal(t, map[string]any{"Name": "a"}, decodeMsgPackAny(t, values[0]))
+}
+
+func TestWriter_WriteStruct_Shadowing(t *testing.T) {
+	type A struct{ ID, X, Y int }
+	type B struct{ ID, X int }
+	type C struct {
+		Y int
+		B
+	}
+	type Obj struct {
+		A
+		C
+		ID string
+	}
+	// Obj.ID hides A.ID and B.ID; A.X and B.X are at different depth; A.Y and C.Y conflict
+	v := Obj{A{1, 2, 3}, C{4, B{5, 6}}, "id"}
+
+	var names []string
+	for _, f := range StructFields(reflect.TypeOf(v)) {
+		names = append(names, f.Name)
+	}
+	w := NewBuffer(nil)
+	w.SetMode(ModeStructs)
+	w.WriteVar(v)
+	var res Obj
+	err := w.ReadVar(&res)
+
+	assert.Equal(t, []string{"A.X", "ID"}, names)
+	assert.NoError(t, err)
+	assert.Equal(t, Obj{A: A{X: 2}, ID: "id"}, res)
+	assert.Equal(t, map[string]any{"X": int64(2), "ID": "id"}, decodeMsgPackAny(t, v))
+}
+
+func TestWriter_WriteStruct_RecursiveEmbedding(t *testing.T) {
+	type Node struct {
+		*Node
+		Val int
+	}
+	ff := StructFields(reflect.TypeOf(Node{}))
+
+	assert.Len(t, ff, 1)
+	assert.Equal(t, "Val", ff[0].Name)
+}
+
+func encodeStructs(values ...any) []byte {
+	w := NewBuffer(nil)
+	w.SetMode(ModeStructs)
+	w.WriteVar(values...)
+	return w.Bytes()
+}
+
+func decodeMsgPackAny(t *testing.T, v any) (res map[string]any) {
+	assert.NoError(t, decodeMsgPack(encodeMsgPack(v), &res))
+	return
+}
+
+type ptrCodec struct {
+	A, B int
+}
+
+func (p *ptrCodec) MarshalBinary() ([]byte, error) {
+	return []byte{byte(p.A), byte(p.B)}, nil
+}
+
+func (p *ptrCodec) UnmarshalBinary(b []byte) error {
+	if len(b) != 2 {
+		return errBinaryDataWasCorrupted
+	}
+	p.A, p.B = int(b[0]), int(b[1])
+	return nil
+}
+
+func TestWriter_WriteStruct_PointerReceiverMethods(t *testing.T) {
+	type obj struct {
+		Name string
+		Val  ptrCodec
+	}
+	v := obj{"x", ptrCodec{5, 6}}
+
+	for _, format := range []Format{FormatBin, FormatMsgPack, FormatCBOR} {
+		w := NewBuffer(nil)
+		w.SetMode(ModeStructs)
+		w.SetFormat(format)
+		w.WriteVar(v, &v, v.Val)
+
+		var res obj
+		var res2 *obj
+		var val ptrCodec
+		err := w.ReadVar(&res, &res2, &val)
+
+		assert.NoError(t, err, format)
+		assert.Equal(t, v, res, format)
+		assert.Equal(t, &v, res2, format)
+		assert.Equal(t, v.Val, val, format)
+	}
+	assert.Equal(t, Encode(1, []byte{5, 6}), Encode(1, ptrCodec{5, 6}))
+}
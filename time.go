@@ -0,0 +1,86 @@
+package bin
+
+import (
+	"sync"
+	"time"
+)
+
+// location kinds of Writer.WriteTimeFull
+const (
+	timeZero  = 0
+	timeUTC   = 1
+	timeLocal = 2
+	timeZone  = 3 // offset and name of zone follow
+)
+
+// maxLocations limits number of zone names looked up in tz database (names come from input data)
+const maxLocations = 1024
+
+var locations = locationCache{limit: maxLocations}
+
+// locationCache caches locations by IANA Time Zone name (nil if name is not found in tz database).
+// When limit of names is reached, names that are not cached are not looked up.
+type locationCache struct {
+	mu    sync.RWMutex
+	locs  map[string]*time.Location
+	limit int
+}
+
+// load returns cached location by IANA Time Zone name or nil
+func (c *locationCache) load(name string) *time.Location {
+	c.mu.RLock()
+	loc, ok := c.locs[name]
+	c.mu.RUnlock()
+	if ok || name == "" {
+		return loc
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if loc, ok = c.locs[name]; ok || len(c.locs) >= c.limit {
+		return loc
+	}
+	if loc, _ = time.LoadLocation(name); c.locs == nil {
+		c.locs = map[string]*time.Location{}
+	}
+	c.locs[name] = loc
+	return loc
+}
+
+// Date is calendar date stored as number of days since 1970-01-01
+type Date int32
+
+const secondsPerDay = 24 * 60 * 60
+
+// DateOf returns calendar date of t in location of t
+func DateOf(t time.Time) Date {
+	y, m, d := t.Date()
+	return Date(time.Date(y, m, d, 0, 0, 0, 0, time.UTC).Unix() / secondsPerDay)
+}
+
+func NewDate(year int, month time.Month, day int) Date {
+	return DateOf(time.Date(year, month, day, 0, 0, 0, 0, time.UTC))
+}
+
+// ParseDate parses date in format "2006-01-02"
+func ParseDate(s string) (Date, error) {
+	t, err := time.Parse(time.DateOnly, s)
+	return DateOf(t), err
+}
+
+// Time returns midnight of date in UTC
+func (d Date) Time() time.Time {
+	return time.Unix(int64(d)*secondsPerDay, 0).UTC()
+}
+
+func (d Date) String() string {
+	return d.Time().Format(time.DateOnly)
+}
+
+func (d Date) MarshalText() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+func (d *Date) UnmarshalText(data []byte) (err error) {
+	*d, err = ParseDate(string(data))
+	return
+}
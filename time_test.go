@@ -0,0 +1,44 @@
+package bin
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDateOf(t *testing.T) {
+	tm := time.Date(2016, 07, 06, 23, 30, 0, 0, time.FixedZone("", 5*3600))
+
+	d := DateOf(tm)
+
+	assert.Equal(t, Date(16988), d)
+	assert.Equal(t, "2016-07-06", d.String())
+	assert.Equal(t, time.Date(2016, 07, 06, 0, 0, 0, 0, time.UTC), d.Time())
+	assert.Equal(t, Date(0), NewDate(1970, 1, 1))
+	assert.Equal(t, Date(-1), NewDate(1969, 12, 31))
+	assert.Equal(t, "1969-12-31", Date(-1).String())
+}
+
+func TestParseDate(t *testing.T) {
+	d, err := ParseDate("2016-07-06")
+	_, err2 := ParseDate("2016-07-32")
+
+	assert.NoError(t, err)
+	assert.Equal(t, NewDate(2016, 07, 06), d)
+	assert.Error(t, err2)
+}
+
+func TestDate_JSON(t *testing.T) {
+	dd := []Date{NewDate(2016, 07, 06), NewDate(1, 1, 1)}
+
+	data, err := json.Marshal(dd)
+	var res []Date
+	err2 := json.Unmarshal(data, &res)
+
+	assert.NoError(t, err)
+	assert.NoError(t, err2)
+	assert.Equal(t, `["2016-07-06","0001-01-01"]`, string(data))
+	assert.Equal(t, dd, res)
+}
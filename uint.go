@@ -1,6 +1,19 @@
 package bin
 
-import "math"
+import (
+	"errors"
+	"math"
+)
+
+type Unsigned interface {
+	~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uint
+}
+
+type Signed interface {
+	~int8 | ~int16 | ~int32 | ~int64 | ~int
+}
+
+var errInvalidDataLength = errors.New("bin.DecodeUint-Error: invalid data length")
 
 func Uint64ToBytes(val uint64) []byte {
 	return []byte{
@@ -100,3 +113,185 @@ func BytesToFloat64(b []byte) float64 {
 func BytesToFloat32(b []byte) float32 {
 	return math.Float32frombits(BytesToUint32(b))
 }
+
+//----------- little-endian --------------
+
+func Uint64ToBytesLE(val uint64) []byte {
+	return AppendUintLE(make([]byte, 0, 8), val)
+}
+
+func Uint32ToBytesLE(val uint32) []byte {
+	return AppendUintLE(make([]byte, 0, 4), val)
+}
+
+func Uint16ToBytesLE(val uint16) []byte {
+	return AppendUintLE(make([]byte, 0, 2), val)
+}
+
+func BytesToUint16LE(b []byte) uint16 {
+	return bytesToUintLE[uint16](b)
+}
+
+func BytesToUint32LE(b []byte) uint32 {
+	return bytesToUintLE[uint32](b)
+}
+
+func BytesToUint64LE(b []byte) uint64 {
+	return bytesToUintLE[uint64](b)
+}
+
+// bytesToUintLE decodes up to sizeof(T) first bytes of b; missing high bytes are zeros.
+func bytesToUintLE[T Unsigned](b []byte) T {
+	if n := sizeOf[T](); len(b) > n {
+		b = b[:n]
+	}
+	var v uint64
+	for i := len(b) - 1; i >= 0; i-- {
+		v = v<<8 | uint64(b[i])
+	}
+	return T(v)
+}
+
+//----------- signed --------------
+
+func Int16ToBytes(val int16) []byte {
+	return Uint16ToBytes(uint16(val))
+}
+
+func Int32ToBytes(val int32) []byte {
+	return Uint32ToBytes(uint32(val))
+}
+
+func Int64ToBytes(val int64) []byte {
+	return Uint64ToBytes(uint64(val))
+}
+
+func BytesToInt16(b []byte) int16 {
+	return int16(BytesToUint16(b))
+}
+
+func BytesToInt32(b []byte) int32 {
+	return int32(BytesToUint32(b))
+}
+
+func BytesToInt64(b []byte) int64 {
+	return int64(BytesToUint64(b))
+}
+
+func Int16ToBytesLE(val int16) []byte {
+	return Uint16ToBytesLE(uint16(val))
+}
+
+func Int32ToBytesLE(val int32) []byte {
+	return Uint32ToBytesLE(uint32(val))
+}
+
+func Int64ToBytesLE(val int64) []byte {
+	return Uint64ToBytesLE(uint64(val))
+}
+
+func BytesToInt16LE(b []byte) int16 {
+	return int16(BytesToUint16LE(b))
+}
+
+func BytesToInt32LE(b []byte) int32 {
+	return int32(BytesToUint32LE(b))
+}
+
+func BytesToInt64LE(b []byte) int64 {
+	return int64(BytesToUint64LE(b))
+}
+
+//----------- generic --------------
+
+// PutUint puts v into b[:sizeof(T)] in big-endian order. It panics if b is too short.
+func PutUint[T Unsigned](b []byte, v T) {
+	n, x := sizeOf[T](), uint64(v)
+	_ = b[n-1]
+	for i := n - 1; i >= 0; i-- {
+		b[i] = byte(x)
+		x >>= 8
+	}
+}
+
+// PutUintLE puts v into b[:sizeof(T)] in little-endian order. It panics if b is too short.
+func PutUintLE[T Unsigned](b []byte, v T) {
+	n, x := sizeOf[T](), uint64(v)
+	_ = b[n-1]
+	for i := 0; i < n; i++ {
+		b[i] = byte(x)
+		x >>= 8
+	}
+}
+
+func AppendUint[T Unsigned](b []byte, v T) []byte {
+	n := len(b)
+	b = append(b, make([]byte, sizeOf[T]())...)
+	PutUint(b[n:], v)
+	return b
+}
+
+func AppendUintLE[T Unsigned](b []byte, v T) []byte {
+	n := len(b)
+	b = append(b, make([]byte, sizeOf[T]())...)
+	PutUintLE(b[n:], v)
+	return b
+}
+
+// DecodeUint decodes big-endian unsigned integer. Length of b must be exactly sizeof(T).
+func DecodeUint[T Unsigned](b []byte) (T, error) {
+	if len(b) != sizeOf[T]() {
+		return 0, errInvalidDataLength
+	}
+	var v uint64
+	for _, c := range b {
+		v = v<<8 | uint64(c)
+	}
+	return T(v), nil
+}
+
+// DecodeUintLE decodes little-endian unsigned integer. Length of b must be exactly sizeof(T).
+func DecodeUintLE[T Unsigned](b []byte) (T, error) {
+	if len(b) != sizeOf[T]() {
+		return 0, errInvalidDataLength
+	}
+	return bytesToUintLE[T](b), nil
+}
+
+// DecodeInt decodes big-endian signed integer. Length of b must be exactly sizeof(T).
+func DecodeInt[T Signed](b []byte) (T, error) {
+	if len(b) != sizeOfInt[T]() {
+		return 0, errInvalidDataLength
+	}
+	var v uint64
+	for _, c := range b {
+		v = v<<8 | uint64(c)
+	}
+	return T(v), nil
+}
+
+// DecodeIntLE decodes little-endian signed integer. Length of b must be exactly sizeof(T).
+func DecodeIntLE[T Signed](b []byte) (T, error) {
+	if len(b) != sizeOfInt[T]() {
+		return 0, errInvalidDataLength
+	}
+	var v uint64
+	for i := len(b) - 1; i >= 0; i-- {
+		v = v<<8 | uint64(b[i])
+	}
+	return T(v), nil
+}
+
+func sizeOfInt[T Signed]() (n int) {
+	for T(1)<<(8*n) != 0 {
+		n++
+	}
+	return
+}
+
+func sizeOf[T Unsigned]() (n int) {
+	for m := uint64(^T(0)); m != 0; m >>= 8 {
+		n++
+	}
+	return
+}
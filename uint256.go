@@ -0,0 +1,247 @@
+package bin
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"math/bits"
+)
+
+// Uint128 and Uint256 are fixed-width unsigned integers.
+// Words are stored in little-endian order (x[0] is the lowest 64 bits).
+// Arithmetic operations wrap around on overflow like native unsigned integers.
+type (
+	Uint128 [2]uint64
+	Uint256 [4]uint64
+)
+
+var errUintOverflow = errors.New("bin.Uint256-Error: value overflow")
+
+func NewUint128(v uint64) Uint128 {
+	return Uint128{v}
+}
+
+func NewUint256(v uint64) Uint256 {
+	return Uint256{v}
+}
+
+// Uint128FromBig converts b to Uint128. It returns false if b is negative or too large.
+func Uint128FromBig(b *big.Int) (x Uint128, ok bool) {
+	ok = limbsFromBig(x[:], b)
+	return
+}
+
+// Uint256FromBig converts b to Uint256. It returns false if b is negative or too large.
+func Uint256FromBig(b *big.Int) (x Uint256, ok bool) {
+	ok = limbsFromBig(x[:], b)
+	return
+}
+
+// Uint128FromBytes converts big-endian bytes to Uint128. Only the last 16 bytes are used.
+func Uint128FromBytes(b []byte) (x Uint128) {
+	limbsFromBytes(x[:], b)
+	return
+}
+
+// Uint256FromBytes converts big-endian bytes to Uint256. Only the last 32 bytes are used.
+func Uint256FromBytes(b []byte) (x Uint256) {
+	limbsFromBytes(x[:], b)
+	return
+}
+
+// ParseUint128 parses decimal or 0x-prefixed hex string.
+func ParseUint128(s string) (x Uint128, err error) {
+	err = parseLimbs(x[:], s)
+	return
+}
+
+// ParseUint256 parses decimal or 0x-prefixed hex string.
+func ParseUint256(s string) (x Uint256, err error) {
+	err = parseLimbs(x[:], s)
+	return
+}
+
+//----------- Uint128 --------------
+
+func (x Uint128) Add(y Uint128) (z Uint128) { addLimbs(z[:], x[:], y[:]); return }
+func (x Uint128) Sub(y Uint128) (z Uint128) { subLimbs(z[:], x[:], y[:]); return }
+func (x Uint128) Mul(y Uint128) (z Uint128) { mulLimbs(z[:], x[:], y[:]); return }
+func (x Uint128) Lsh(n uint) (z Uint128)    { lshLimbs(z[:], x[:], n); return }
+func (x Uint128) Rsh(n uint) (z Uint128)    { rshLimbs(z[:], x[:], n); return }
+func (x Uint128) Cmp(y Uint128) int         { return cmpLimbs(x[:], y[:]) }
+func (x Uint128) IsZero() bool              { return x == Uint128{} }
+func (x Uint128) Uint64() uint64            { return x[0] }
+func (x Uint128) Bytes() []byte             { return limbsToBytes(x[:]) }
+func (x Uint128) Big() *big.Int             { return new(big.Int).SetBytes(x.Bytes()) }
+func (x Uint128) String() string            { return x.Big().String() }
+
+// Text and JSON forms are fixed-width hex like Bytes (String is decimal).
+
+func (x Uint128) MarshalText() ([]byte, error)     { return Bytes(x.Bytes()).MarshalText() }
+func (x Uint128) MarshalJSON() ([]byte, error)     { return Bytes(x.Bytes()).MarshalJSON() }
+func (x *Uint128) UnmarshalText(data []byte) error { return unmarshalLimbsText(x[:], string(data)) }
+func (x *Uint128) UnmarshalJSON(data []byte) error { return unmarshalLimbsJSON(x[:], data) }
+
+//----------- Uint256 --------------
+
+func (x Uint256) Add(y Uint256) (z Uint256) { addLimbs(z[:], x[:], y[:]); return }
+func (x Uint256) Sub(y Uint256) (z Uint256) { subLimbs(z[:], x[:], y[:]); return }
+func (x Uint256) Mul(y Uint256) (z Uint256) { mulLimbs(z[:], x[:], y[:]); return }
+func (x Uint256) Lsh(n uint) (z Uint256)    { lshLimbs(z[:], x[:], n); return }
+func (x Uint256) Rsh(n uint) (z Uint256)    { rshLimbs(z[:], x[:], n); return }
+func (x Uint256) Cmp(y Uint256) int         { return cmpLimbs(x[:], y[:]) }
+func (x Uint256) IsZero() bool              { return x == Uint256{} }
+func (x Uint256) Uint64() uint64            { return x[0] }
+func (x Uint256) Bytes() []byte             { return limbsToBytes(x[:]) }
+func (x Uint256) Big() *big.Int             { return new(big.Int).SetBytes(x.Bytes()) }
+func (x Uint256) String() string            { return x.Big().String() }
+
+// Text and JSON forms are fixed-width hex like Bytes (String is decimal).
+
+func (x Uint256) MarshalText() ([]byte, error)     { return Bytes(x.Bytes()).MarshalText() }
+func (x Uint256) MarshalJSON() ([]byte, error)     { return Bytes(x.Bytes()).MarshalJSON() }
+func (x *Uint256) UnmarshalText(data []byte) error { return unmarshalLimbsText(x[:], string(data)) }
+func (x *Uint256) UnmarshalJSON(data []byte) error { return unmarshalLimbsJSON(x[:], data) }
+
+//----------- limbs arithmetic --------------
+
+func addLimbs(z, x, y []uint64) {
+	var c uint64
+	for i := range z {
+		z[i], c = bits.Add64(x[i], y[i], c)
+	}
+}
+
+func subLimbs(z, x, y []uint64) {
+	var b uint64
+	for i := range z {
+		z[i], b = bits.Sub64(x[i], y[i], b)
+	}
+}
+
+// mulLimbs sets z to low len(z) words of x*y. z must be zeroed and must not overlap x or y.
+func mulLimbs(z, x, y []uint64) {
+	for i := range x {
+		var carry uint64
+		for j := 0; i+j < len(z); j++ {
+			hi, lo := bits.Mul64(x[i], y[j])
+			var c uint64
+			lo, c = bits.Add64(lo, z[i+j], 0)
+			hi += c
+			lo, c = bits.Add64(lo, carry, 0)
+			hi += c
+			z[i+j], carry = lo, hi
+		}
+	}
+}
+
+func lshLimbs(z, x []uint64, n uint) {
+	words, shift := int(n/64), n%64
+	for i := len(z) - 1; i >= 0; i-- {
+		var v uint64
+		if j := i - words; j >= 0 {
+			v = x[j] << shift
+			if shift > 0 && j > 0 {
+				v |= x[j-1] >> (64 - shift)
+			}
+		}
+		z[i] = v
+	}
+}
+
+func rshLimbs(z, x []uint64, n uint) {
+	words, shift := int(n/64), n%64
+	for i := range z {
+		var v uint64
+		if j := i + words; j >= 0 && j < len(x) {
+			v = x[j] >> shift
+			if shift > 0 && j+1 < len(x) {
+				v |= x[j+1] << (64 - shift)
+			}
+		}
+		z[i] = v
+	}
+}
+
+func cmpLimbs(x, y []uint64) int {
+	for i := len(x) - 1; i >= 0; i-- {
+		if x[i] != y[i] {
+			if x[i] < y[i] {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
+
+//----------- limbs conversion --------------
+
+// limbsToBytes returns big-endian fixed-width bytes
+func limbsToBytes(x []uint64) []byte {
+	b := make([]byte, len(x)*8)
+	for i, v := range x {
+		binary.BigEndian.PutUint64(b[len(b)-8*(i+1):], v)
+	}
+	return b
+}
+
+func limbsFromBytes(z []uint64, b []byte) {
+	var buf [32]byte
+	n := len(z) * 8
+	if len(b) > n {
+		b = b[len(b)-n:]
+	}
+	copy(buf[n-len(b):n], b)
+	for i := range z {
+		z[i] = binary.BigEndian.Uint64(buf[n-8*(i+1):])
+	}
+}
+
+func limbsFromBig(z []uint64, b *big.Int) bool {
+	if b.Sign() < 0 || b.BitLen() > len(z)*64 {
+		return false
+	}
+	limbsFromBytes(z, b.Bytes())
+	return true
+}
+
+func parseLimbs(z []uint64, s string) error {
+	b, ok := new(big.Int).SetString(s, 0)
+	if !ok {
+		return errors.New("bin.ParseUint256-Error: invalid number " + s)
+	}
+	if !limbsFromBig(z, b) {
+		return errUintOverflow
+	}
+	return nil
+}
+
+// unmarshalLimbsText decodes hex with optional 0x prefix; shorter text is padded with leading zeros
+func unmarshalLimbsText(z []uint64, s string) error {
+	b, err := decodeHex(s)
+	if err != nil {
+		return err
+	}
+	if len(b) > len(z)*8 {
+		return errUintOverflow
+	}
+	limbsFromBytes(z, b)
+	return nil
+}
+
+// unmarshalLimbsJSON accepts hex string, JSON number or null (null leaves value unchanged)
+func unmarshalLimbsJSON(z []uint64, data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+	if len(data) > 0 && data[0] == '"' {
+		var s string
+		if err := json.Unmarshal(data, &s); err != nil {
+			return err
+		}
+		return unmarshalLimbsText(z, s)
+	}
+	return parseLimbs(z, string(data))
+}
@@ -0,0 +1,143 @@
+package bin
+
+import (
+	"encoding/json"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUint256_Arithmetic(t *testing.T) {
+	a := newUint256("ffeeddccbbaa99887766554433221100ffeeddccbbaa9988")
+	b := newUint256("123456789abcdef0123456789abcdef")
+	mod := new(big.Int).Lsh(big.NewInt(1), 256)
+
+	bigOp := func(op func(z, x, y *big.Int) *big.Int) Uint256 {
+		z := op(new(big.Int), a.Big(), b.Big())
+		x, _ := Uint256FromBig(z.Mod(z, mod))
+		return x
+	}
+
+	assert.Equal(t, bigOp((*big.Int).Add), a.Add(b))
+	assert.Equal(t, bigOp((*big.Int).Sub), a.Sub(b))
+	assert.Equal(t, bigOp((*big.Int).Sub).Big(), new(big.Int).Sub(a.Big(), b.Big()))
+	assert.Equal(t, bigOp((*big.Int).Mul), a.Mul(b))
+	assert.Equal(t, bigOp((*big.Int).Mul), b.Mul(a))
+	assert.Equal(t, 1, a.Cmp(b))
+	assert.Equal(t, -1, b.Cmp(a))
+	assert.Equal(t, 0, a.Cmp(a))
+}
+
+func TestUint256_Overflow(t *testing.T) {
+	max := Uint256{}.Sub(NewUint256(1))
+
+	assert.Equal(t, Uint256{^uint64(0), ^uint64(0), ^uint64(0), ^uint64(0)}, max)
+	assert.True(t, max.Add(NewUint256(1)).IsZero())
+	assert.Equal(t, NewUint128(1), Uint128{^uint64(0), ^uint64(0)}.Mul(Uint128{^uint64(0), ^uint64(0)}))
+}
+
+func TestUint256_Shift(t *testing.T) {
+	a := newUint256("ffeeddccbbaa99887766554433221100ffeeddccbbaa9988")
+	mask := new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 256), big.NewInt(1))
+
+	for _, n := range []uint{0, 1, 8, 63, 64, 65, 130, 255, 256, 300} {
+		lsh := new(big.Int).Lsh(a.Big(), n)
+		rsh := new(big.Int).Rsh(a.Big(), n)
+
+		assert.Equal(t, lsh.And(lsh, mask).String(), a.Lsh(n).String(), n)
+		assert.Equal(t, rsh.String(), a.Rsh(n).String(), n)
+	}
+}
+
+func TestUint256FromBig(t *testing.T) {
+	_, ok1 := Uint256FromBig(big.NewInt(-1))
+	_, ok2 := Uint256FromBig(new(big.Int).Lsh(big.NewInt(1), 256))
+	_, ok3 := Uint128FromBig(new(big.Int).Lsh(big.NewInt(1), 128))
+	x, ok4 := Uint128FromBig(newBigInt("ffeeddccbbaa99887766554433221100"))
+
+	assert.False(t, ok1)
+	assert.False(t, ok2)
+	assert.False(t, ok3)
+	assert.True(t, ok4)
+	assert.Equal(t, Uint128{0x7766554433221100, 0xffeeddccbbaa9988}, x)
+	assert.Equal(t, "340193404210632335760508365704335069440", x.String())
+}
+
+func TestUint256_JSON(t *testing.T) {
+	a := newUint256("ffeeddccbbaa99887766554433221100")
+
+	data, err := json.Marshal([]Uint256{a, {}})
+	var res []Uint256
+	err2 := json.Unmarshal(data, &res)
+	var b Uint256
+	err3 := json.Unmarshal([]byte(`"0xff"`), &b)
+	var c Uint128
+	err4 := json.Unmarshal([]byte(`123`), &c)
+	d := NewUint128(5)
+	err5 := json.Unmarshal([]byte(`null`), &d)
+	var e Uint128
+	err6 := json.Unmarshal([]byte(`"01`+strings.Repeat("00", 16)+`"`), &e)
+
+	assert.NoError(t, err)
+	assert.NoError(t, err2)
+	assert.NoError(t, err3)
+	assert.NoError(t, err4)
+	assert.NoError(t, err5)
+	assert.ErrorIs(t, err6, errUintOverflow)
+	assert.Equal(t, `["`+strings.Repeat("0", 32)+`ffeeddccbbaa99887766554433221100","`+strings.Repeat("0", 64)+`"]`, string(data))
+	assert.Equal(t, []Uint256{a, {}}, res)
+	assert.Equal(t, NewUint256(255), b)
+	assert.Equal(t, NewUint128(123), c)
+	assert.Equal(t, NewUint128(5), d)
+}
+
+func TestUint128_Text(t *testing.T) {
+	a := NewUint128(0x0102)
+
+	text, err := a.MarshalText()
+	var res Uint128
+	err2 := res.UnmarshalText(text)
+
+	assert.NoError(t, err)
+	assert.NoError(t, err2)
+	assert.Equal(t, "00000000000000000000000000000102", string(text))
+	assert.Equal(t, "258", a.String())
+	assert.Equal(t, a, res)
+}
+
+func TestUint256_Encode(t *testing.T) {
+	a := newUint256("ffeeddccbbaa99887766554433221100ffeeddccbbaa9988")
+	b := NewUint128(100)
+
+	data := Encode(a, b)
+	var (
+		a1 Uint256
+		b1 Uint128
+		i1 *big.Int
+	)
+	err := Decode(data, &a1, &b1)
+	err2 := Decode(data, &i1)
+
+	assert.NoError(t, err)
+	assert.NoError(t, err2)
+	assert.Equal(t, Encode(a.Big(), b.Big()), data)
+	assert.Equal(t, a, a1)
+	assert.Equal(t, b, b1)
+	assert.Equal(t, a.Big(), i1)
+}
+
+func TestUint256_DecodeOverflow(t *testing.T) {
+	data := Encode(newBigInt("1ffeeddccbbaa99887766554433221100"))
+
+	var x Uint128
+	err := Decode(data, &x)
+
+	assert.Error(t, err)
+}
+
+func newUint256(hex string) Uint256 {
+	x, _ := Uint256FromBig(newBigInt(hex))
+	return x
+}
@@ -3,6 +3,7 @@ package bin
 import (
 	"encoding/hex"
 	"fmt"
+	"math"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -59,3 +60,81 @@ func TestBytesToFloat64(t *testing.T) {
 	assert.Equal(t, 8, len(b))
 	assert.Equal(t, org64, dec64)
 }
+
+func TestUint64ToBytesLE(t *testing.T) {
+	val := uint64(0xfedcba9876543210)
+
+	b := Uint64ToBytesLE(val)
+
+	assert.Equal(t, "1032547698badcfe", fmt.Sprintf("%x", b))
+	assert.Equal(t, val, BytesToUint64LE(b))
+}
+
+func TestBytesToUint32LE_WithNulls(t *testing.T) {
+	b, _ := hex.DecodeString("102030")
+
+	val := BytesToUint32LE(b)
+
+	assert.Equal(t, uint32(0x302010), val)
+}
+
+func TestBytesToInt16(t *testing.T) {
+	assert.Equal(t, []byte{0xff, 0xfe}, Int16ToBytes(-2))
+	assert.Equal(t, []byte{0xfe, 0xff}, Int16ToBytesLE(-2))
+	assert.Equal(t, int16(-2), BytesToInt16([]byte{0xff, 0xfe}))
+	assert.Equal(t, int32(-2), BytesToInt32LE([]byte{0xfe, 0xff, 0xff, 0xff}))
+	assert.Equal(t, int64(-1234567890123), BytesToInt64(Int64ToBytes(-1234567890123)))
+}
+
+func TestPutUint(t *testing.T) {
+	type myUint uint32
+	b := make([]byte, 4)
+	b2 := make([]byte, 4)
+
+	PutUint(b, myUint(0x01020304))
+	PutUintLE(b2, myUint(0x01020304))
+
+	assert.Equal(t, []byte{1, 2, 3, 4}, b)
+	assert.Equal(t, []byte{4, 3, 2, 1}, b2)
+	assert.Equal(t, []byte{0xff, 1, 2}, AppendUint([]byte{0xff}, uint16(0x0102)))
+	assert.Equal(t, []byte{0xff, 2, 1}, AppendUintLE([]byte{0xff}, uint16(0x0102)))
+	assert.Equal(t, []byte{0, 0, 0, 0, 0, 0, 0, 7}, AppendUint(nil, uint(7)))
+}
+
+func TestDecodeUint(t *testing.T) {
+	v1, err1 := DecodeUint[uint32]([]byte{1, 2, 3, 4})
+	v2, err2 := DecodeUintLE[uint16]([]byte{1, 2})
+	_, err3 := DecodeUint[uint32]([]byte{1, 2, 3})
+	_, err4 := DecodeUintLE[uint64]([]byte{1, 2, 3, 4, 5, 6, 7, 8, 9})
+
+	assert.NoError(t, err1)
+	assert.NoError(t, err2)
+	assert.Equal(t, uint32(0x01020304), v1)
+	assert.Equal(t, uint16(0x0201), v2)
+	assert.Error(t, err3)
+	assert.Error(t, err4)
+}
+
+func TestDecodeInt(t *testing.T) {
+	v1, err1 := DecodeInt[int32]([]byte{0xff, 0xff, 0xff, 0xfe})
+	v2, err2 := DecodeIntLE[int16]([]byte{0xfe, 0xff})
+	v3, err3 := DecodeInt[int8]([]byte{0x80})
+	v4, err4 := DecodeIntLE[int]([]byte{1, 0, 0, 0, 0, 0, 0, 0x80})
+	_, err5 := DecodeInt[int32]([]byte{0xff, 0xfe})                  // short
+	_, err6 := DecodeIntLE[int64]([]byte{1, 2, 3, 4, 5, 6, 7, 8, 9}) // long
+	_, err7 := DecodeInt[int16]([]byte{1, 2, 3})
+	_, err8 := DecodeIntLE[int8](nil)
+
+	assert.NoError(t, err1)
+	assert.NoError(t, err2)
+	assert.NoError(t, err3)
+	assert.NoError(t, err4)
+	assert.Equal(t, int32(-2), v1)
+	assert.Equal(t, int16(-2), v2)
+	assert.Equal(t, int8(-128), v3)
+	assert.Equal(t, math.MinInt64+1, v4)
+	assert.Error(t, err5)
+	assert.Error(t, err6)
+	assert.Error(t, err7)
+	assert.Error(t, err8)
+}
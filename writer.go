@@ -3,10 +3,15 @@ package bin
 import (
 	"bytes"
 	"encoding"
+	"encoding/binary"
 	"encoding/gob"
+	"errors"
 	"io"
 	"math"
 	"math/big"
+	"net"
+	"net/netip"
+	"net/url"
 	"reflect"
 	"time"
 )
@@ -14,6 +19,9 @@ import (
 type Writer struct {
 	wr         io.Writer
 	err        error
+	mode       Mode
+	format     Format
+	strs       map[string]int // string table (ModeStringTable)
 	CntWritten int64
 }
 
@@ -47,12 +55,9 @@ func (w *Writer) write(bb []byte) error {
 }
 
 func (w *Writer) Write(bb []byte) (n int, err error) {
-	if buf, ok := w.wr.(*bytes.Buffer); ok {
-		n, err = buf.Write(bb)
-	} else {
-		var n64 int64
-		n64, err = io.Copy(w.wr, bytes.NewBuffer(bb))
-		n = int(n64)
+	n, err = w.wr.Write(bb)
+	if err == nil && n < len(bb) {
+		err = io.ErrShortWrite
 	}
 	w.CntWritten += int64(n)
 	w.SetError(err)
@@ -84,6 +89,18 @@ func (w *Writer) WriteUint64(i uint64) error {
 	return w.write(Uint64ToBytes(i))
 }
 
+func (w *Writer) WriteUint16LE(i uint16) error {
+	return w.write(Uint16ToBytesLE(i))
+}
+
+func (w *Writer) WriteUint32LE(i uint32) error {
+	return w.write(Uint32ToBytesLE(i))
+}
+
+func (w *Writer) WriteUint64LE(i uint64) error {
+	return w.write(Uint64ToBytesLE(i))
+}
+
 func (w *Writer) WriteFloat32(f float32) error {
 	return w.write(Uint32ToBytes(math.Float32bits(f)))
 }
@@ -92,6 +109,24 @@ func (w *Writer) WriteFloat64(f float64) error {
 	return w.write(Uint64ToBytes(math.Float64bits(f)))
 }
 
+func (w *Writer) WriteComplex64(c complex64) error {
+	w.WriteFloat32(real(c))
+	return w.WriteFloat32(imag(c))
+}
+
+func (w *Writer) WriteComplex128(c complex128) error {
+	w.WriteFloat64(real(c))
+	return w.WriteFloat64(imag(c))
+}
+
+func (w *Writer) WriteFloat16(f Float16) error {
+	return w.write(Uint16ToBytes(uint16(f)))
+}
+
+func (w *Writer) WriteBFloat16(f BFloat16) error {
+	return w.write(Uint16ToBytes(uint16(f)))
+}
+
 func (w *Writer) WriteTime(t time.Time) error {
 	return w.write(Uint64ToBytes(uint64(t.UnixNano())))
 }
@@ -114,25 +149,32 @@ func (w *Writer) WriteVarInt(num int) error {
 }
 
 func (w *Writer) WriteVarUint64(num uint64) error {
+	if w.mode&ModeLEB128 != 0 {
+		return w.write(binary.AppendUvarint(nil, num))
+	}
 	return w.WriteVarInt64(int64(num))
 }
 
 func (w *Writer) WriteVarInt64(i int64) error {
+	if w.mode&ModeLEB128 != 0 {
+		return w.write(binary.AppendVarint(nil, i))
+	}
 	if i >= 0 && i < 128 {
 		return w.write([]byte{byte(i)})
 	}
 	var h byte = 0x80
+	u := uint64(i) // magnitude (-MinInt64 overflows int64, but not uint64)
 	if i < 0 {
 		h |= 0x40
-		i = -i
+		u = -u
 	}
 	const bufMaxLen = 8 + 1
 	buf := make([]byte, bufMaxLen)
 	var n byte = 0
-	for i > 0 {
+	for u > 0 {
 		n++
-		buf[bufMaxLen-n] = byte(i)
-		i >>= 8
+		buf[bufMaxLen-n] = byte(u)
+		u >>= 8
 	}
 	buf[bufMaxLen-1-n] = h | n
 	return w.write(buf[bufMaxLen-1-n:])
@@ -169,27 +211,296 @@ func (w *Writer) WriteBigInt(i *big.Int) error {
 	return w.write(b)
 }
 
+// WriteBigRat writes numerator and denominator of x (nil is written as zero)
+func (w *Writer) WriteBigRat(x *big.Rat) error {
+	if x == nil {
+		x = new(big.Rat)
+	}
+	w.WriteBigInt(x.Num())
+	return w.WriteBigInt(x.Denom())
+}
+
+// header flags of WriteBigFloat
+const (
+	bigFloatNeg    = 0x01
+	bigFloatFinite = 0x02
+	bigFloatInf    = 0x04
+)
+
+// WriteBigFloat writes x exactly: sign and form, precision, rounding mode, exponent and mantissa.
+// nil is written as zero.
+func (w *Writer) WriteBigFloat(x *big.Float) error {
+	if x == nil {
+		x = new(big.Float)
+	}
+	var h byte
+	if x.Signbit() {
+		h |= bigFloatNeg
+	}
+	if x.IsInf() {
+		h |= bigFloatInf
+	} else if x.Sign() != 0 {
+		h |= bigFloatFinite
+	}
+	w.WriteByte(h)
+	w.WriteVarUint64(uint64(x.Prec()))
+	w.WriteByte(byte(x.Mode()))
+	if h&bigFloatFinite != 0 {
+		// x = mant * 2**exp, where mant is integer of prec bits
+		mant := new(big.Float)
+		exp := x.MantExp(mant) - int(x.Prec())
+		m, _ := mant.SetMantExp(mant, int(x.Prec())).Int(nil)
+		w.WriteVarInt(exp)
+		w.WriteBigInt(m.Abs(m))
+	}
+	return w.err
+}
+
+// WriteTimeFull writes time with nanosecond precision in full range of time.Time.
+// Location of time is preserved (UTC, Local or zone name with offset); zero time is written as one byte.
+func (w *Writer) WriteTimeFull(t time.Time) error {
+	if t.IsZero() {
+		return w.WriteByte(timeZero)
+	}
+	w.writeLocation(t)
+	w.WriteVarInt64(t.Unix())
+	return w.WriteVarInt(t.Nanosecond())
+}
+
+// writeLocation writes location kind of t (and offset and name of zone)
+func (w *Writer) writeLocation(t time.Time) error {
+	switch loc := t.Location(); loc {
+	case time.UTC:
+		return w.WriteByte(timeUTC)
+	case time.Local:
+		return w.WriteByte(timeLocal)
+	default:
+		_, offset := t.Zone()
+		w.WriteByte(timeZone)
+		w.WriteVarInt(offset)
+		return w.WriteString(loc.String())
+	}
+}
+
+// WriteTimeSec writes unix time in seconds as signed var int (full range of time.Time)
+func (w *Writer) WriteTimeSec(t time.Time) error {
+	return w.WriteVarInt64(t.Unix())
+}
+
+// WriteDate writes date as signed var int of days since epoch
+func (w *Writer) WriteDate(d Date) error {
+	return w.WriteVarInt64(int64(d))
+}
+
+// WriteUint128 writes x as 16 bytes in big-endian order
+func (w *Writer) WriteUint128(x Uint128) error {
+	return w.write(x.Bytes())
+}
+
+// WriteUint256 writes x as 32 bytes in big-endian order
+func (w *Writer) WriteUint256(x Uint256) error {
+	return w.write(x.Bytes())
+}
+
+// WriteVarUint128 writes x in variable-length form compatible with WriteBigInt
+func (w *Writer) WriteVarUint128(x Uint128) error {
+	return w.writeVarUint(x.Bytes())
+}
+
+// WriteVarUint256 writes x in variable-length form compatible with WriteBigInt
+func (w *Writer) WriteVarUint256(x Uint256) error {
+	return w.writeVarUint(x.Bytes())
+}
+
+func (w *Writer) writeVarUint(b []byte) error {
+	for len(b) > 0 && b[0] == 0 {
+		b = b[1:]
+	}
+	if n := len(b); n == 0 {
+		return w.write([]byte{0})
+	} else if n == 1 && b[0] < 128 {
+		return w.write(b)
+	} else {
+		return w.write(append([]byte{0x80 | byte(n)}, b...))
+	}
+}
+
+// address families of WriteAddr.
+// Before native encoding netip types were written as WriteBytes(MarshalBinary()),
+// so other values of the first byte are read as length of the legacy form.
+const (
+	addrNone     = 0
+	addrIPv4     = 4 // 4 bytes follow (the same as legacy form of Addr)
+	addrIPv6     = 8 // 16 bytes follow
+	addrIPv6Zone = 9 // 16 bytes and zone name follow
+)
+
+var errInvalidIPLength = errors.New("bin.WriteIP-Error: invalid length of IP address")
+
+// WriteAddr writes IP address as family byte and 4 or 16 bytes of address
+func (w *Writer) WriteAddr(a netip.Addr) error {
+	switch {
+	case !a.IsValid():
+		return w.WriteByte(addrNone)
+	case a.Is4():
+		b := a.As4()
+		w.WriteByte(addrIPv4)
+		return w.write(b[:])
+	case a.Zone() != "":
+		b := a.As16()
+		w.WriteByte(addrIPv6Zone)
+		w.write(b[:])
+		return w.WriteString(a.Zone())
+	default:
+		b := a.As16()
+		w.WriteByte(addrIPv6)
+		return w.write(b[:])
+	}
+}
+
+// WritePrefix writes address and bits of prefix (invalid prefix is written as invalid address)
+func (w *Writer) WritePrefix(p netip.Prefix) error {
+	if !p.IsValid() {
+		return w.WriteByte(addrNone)
+	}
+	if w.WriteAddr(p.Addr()) == nil {
+		w.WriteByte(byte(p.Bits()))
+	}
+	return w.err
+}
+
+func (w *Writer) WriteAddrPort(ap netip.AddrPort) error {
+	if w.WriteAddr(ap.Addr()) == nil && ap.Addr().IsValid() {
+		w.WriteUint16(ap.Port())
+	}
+	return w.err
+}
+
+// WriteIP writes net.IP in the same form as WriteAddr (nil is written as invalid address)
+func (w *Writer) WriteIP(ip net.IP) error {
+	if ip4 := ip.To4(); ip4 != nil {
+		ip = ip4
+	}
+	a, ok := netip.AddrFromSlice(ip)
+	if !ok && len(ip) != 0 {
+		w.SetError(errInvalidIPLength)
+		return w.err
+	}
+	return w.WriteAddr(a)
+}
+
+// WriteURL writes url as string (nil is written as empty string)
+func (w *Writer) WriteURL(u *url.URL) error {
+	if u == nil {
+		return w.WriteString("")
+	}
+	return w.WriteString(u.String())
+}
+
+// writeLen writes length of slice or map (nil is written as -1 in ModeNilEmpty)
+func (w *Writer) writeLen(n int, isNil bool) error {
+	if isNil && w.mode&ModeNilEmpty != 0 {
+		n = -1
+	}
+	if w.mode&ModeLEB128 != 0 {
+		return w.WriteVarUint64(uint64(n))
+	}
+	return w.WriteVarInt(n)
+}
+
 func (w *Writer) WriteSliceBytes(bb [][]byte) error {
-	w.WriteVarInt(len(bb))
+	w.writeLen(len(bb), bb == nil)
 	for _, d := range bb {
 		w.WriteBytes(d)
 	}
 	return w.err
 }
 
+func (w *Writer) WriteFloat16s(ff []Float16) error {
+	w.writeLen(len(ff), ff == nil)
+	return w.write(uint16sToBytes(ff))
+}
+
+func (w *Writer) WriteBFloat16s(ff []BFloat16) error {
+	w.writeLen(len(ff), ff == nil)
+	return w.write(uint16sToBytes(ff))
+}
+
+func (w *Writer) WriteDeltaInts(vv []int64) error {
+	return w.writeDeltas(append([]int64(nil), vv...), vv == nil, 1)
+}
+
+func (w *Writer) WriteDelta2Ints(vv []int64) error {
+	return w.writeDeltas(append([]int64(nil), vv...), vv == nil, 2)
+}
+
+func (w *Writer) WriteDeltaUints(vv []uint64) error {
+	return w.writeDeltas(uintsToInts(vv), vv == nil, 1)
+}
+
+func (w *Writer) WriteDeltaTimes(tt []time.Time) error {
+	return w.writeDeltaTimes(tt, 1)
+}
+
+func (w *Writer) WriteDelta2Times(tt []time.Time) error {
+	return w.writeDeltaTimes(tt, 2)
+}
+
+func (w *Writer) writeDeltas(vv []int64, isNil bool, order int) error {
+	w.writeLen(len(vv), isNil)
+	return w.writeDeltaValues(vv, order)
+}
+
+// writeDeltaValues writes deltas of given order of values (vv is changed)
+func (w *Writer) writeDeltaValues(vv []int64, order int) error {
+	toDeltas(vv, order)
+	for _, v := range vv {
+		if w.WriteVarInt64(v) != nil {
+			break
+		}
+	}
+	return w.err
+}
+
+// writeDeltaTimes writes length, location of the first time, deltas of seconds and deltas of nanoseconds
+func (w *Writer) writeDeltaTimes(tt []time.Time, order int) error {
+	if w.writeLen(len(tt), tt == nil) != nil || len(tt) == 0 {
+		return w.err
+	}
+	w.writeLocation(tt[0])
+	secs, nsecs := make([]int64, len(tt)), make([]int64, len(tt))
+	for i, t := range tt {
+		secs[i], nsecs[i] = t.Unix(), int64(t.Nanosecond())
+	}
+	w.writeDeltaValues(secs, order)
+	return w.writeDeltaValues(nsecs, order)
+}
+
+func uint16sToBytes[T ~uint16](vv []T) []byte {
+	b := make([]byte, 2*len(vv))
+	for i, v := range vv {
+		PutUint(b[2*i:], v)
+	}
+	return b
+}
+
 func (w *Writer) WriteBytes(bb []byte) error {
-	w.WriteVarInt(len(bb))
+	w.writeLen(len(bb), bb == nil)
 	w.Write(bb)
 	return w.err
 }
 
 func (w *Writer) WriteString(s string) error {
-	w.WriteBytes([]byte(s))
+	if w.mode&ModeStringTable != 0 {
+		return w.writeStringRef(s)
+	}
+	w.writeLen(len(s), false)
+	w.Write([]byte(s))
 	return w.err
 }
 
 func (w *Writer) WriteStrings(ss []string) error {
-	w.WriteVarInt(len(ss))
+	w.writeLen(len(ss), ss == nil)
 	for _, s := range ss {
 		if w.WriteString(s) != nil {
 			break
@@ -212,6 +523,15 @@ func (w *Writer) WriteVar(val ...interface{}) error {
 }
 
 func (w *Writer) writeVar(val interface{}) error {
+	if v, ok := val.(RawValue); ok {
+		return w.writeRawValue(v)
+	}
+	switch w.format {
+	case FormatMsgPack:
+		return w.writeMsgPack(val)
+	case FormatCBOR:
+		return w.writeCBOR(val)
+	}
 	switch v := val.(type) {
 	case nil:
 		w.WriteNil()
@@ -228,22 +548,42 @@ func (w *Writer) writeVar(val interface{}) error {
 		w.WriteVarInt64(int64(v))
 
 	case uint:
-		w.WriteVarInt64(int64(v))
+		w.WriteVarUint64(uint64(v))
 	case uint8:
-		w.WriteVarInt64(int64(v))
+		w.WriteVarUint64(uint64(v))
 	case uint16:
-		w.WriteVarInt64(int64(v))
+		w.WriteVarUint64(uint64(v))
 	case uint32:
-		w.WriteVarInt64(int64(v))
+		w.WriteVarUint64(uint64(v))
 	case uint64:
-		w.WriteVarInt64(int64(v))
+		w.WriteVarUint64(uint64(v))
 
 	case float32:
 		w.WriteFloat32(v)
 	case float64:
 		w.WriteFloat64(v)
+	case complex64:
+		w.WriteComplex64(v)
+	case complex128:
+		w.WriteComplex128(v)
+	case Float16:
+		w.WriteFloat16(v)
+	case BFloat16:
+		w.WriteBFloat16(v)
+	case []Float16:
+		w.WriteFloat16s(v)
+	case []BFloat16:
+		w.WriteBFloat16s(v)
 	case time.Time:
-		w.WriteTime(v)
+		if w.mode&ModeTimeFull != 0 {
+			w.WriteTimeFull(v)
+		} else {
+			w.WriteTime(v)
+		}
+	case time.Duration:
+		w.WriteVarInt64(int64(v))
+	case Date:
+		w.WriteDate(v)
 	case bool:
 		w.WriteBool(v)
 
@@ -262,6 +602,31 @@ func (w *Writer) writeVar(val interface{}) error {
 		w.WriteBigInt(v)
 	case big.Int:
 		w.WriteBigInt(&v)
+	case *big.Rat:
+		w.WriteBigRat(v)
+	case big.Rat:
+		w.WriteBigRat(&v)
+	case *big.Float:
+		w.WriteBigFloat(v)
+	case big.Float:
+		w.WriteBigFloat(&v)
+	case netip.Addr:
+		w.WriteAddr(v)
+	case netip.Prefix:
+		w.WritePrefix(v)
+	case netip.AddrPort:
+		w.WriteAddrPort(v)
+	case net.IP:
+		w.WriteIP(v)
+	case *url.URL:
+		w.WriteURL(v)
+	case url.URL:
+		w.WriteURL(&v)
+
+	case Uint128:
+		w.WriteVarUint128(v)
+	case Uint256:
+		w.WriteVarUint256(v)
 
 	case binaryEncoder:
 		if isNil(val) {
@@ -294,11 +659,16 @@ func (w *Writer) writeVar(val interface{}) error {
 	default:
 
 		rv := reflect.ValueOf(v)
+		if hasPtrEncoder(rv.Type()) {
+			p := reflect.New(rv.Type())
+			p.Elem().Set(rv)
+			return w.writeVar(p.Interface())
+		}
 		switch rv.Kind() {
 
 		case reflect.Slice:
 			n := rv.Len()
-			w.WriteVarInt(n)
+			w.writeLen(n, rv.IsNil())
 			for i := 0; i < n; i++ {
 				vi := rv.Index(i)
 				w.WriteVar(vi.Interface())
@@ -306,12 +676,29 @@ func (w *Writer) writeVar(val interface{}) error {
 
 		case reflect.Map:
 			keys := rv.MapKeys()
-			w.WriteVarInt(len(keys))
+			w.writeLen(len(keys), rv.IsNil())
 			for _, key := range keys {
 				w.WriteVar(key.Interface())
 				w.WriteVar(rv.MapIndex(key).Interface())
 			}
 
+		case reflect.Struct:
+			if w.mode&ModeStructs != 0 {
+				w.writeStruct(rv)
+			} else {
+				w.err = gob.NewEncoder(w).Encode(v)
+			}
+
+		case reflect.Ptr:
+			if w.mode&ModeStructs != 0 && isPlainStruct(rv.Type().Elem()) {
+				// presence byte and struct
+				if w.WriteBool(!rv.IsNil()) == nil && !rv.IsNil() {
+					w.writeStruct(rv.Elem())
+				}
+			} else {
+				w.err = gob.NewEncoder(w).Encode(v)
+			}
+
 		default:
 			w.err = gob.NewEncoder(w).Encode(v)
 		}
@@ -80,6 +80,21 @@ func TestWriter_WriteString(t *testing.T) {
 	assert.Equal(t, []byte{0, 3, 'A', 'b', 'c'}, w.Bytes())
 }
 
+func TestWriter_WriteUint256(t *testing.T) {
+	w := NewBuffer(nil)
+	w.WriteUint128(NewUint128(0x0102))
+	w.WriteUint256(NewUint256(0x0304))
+
+	r := w.Reader
+	x, err1 := r.ReadUint128()
+	y, err2 := r.ReadUint256()
+
+	assert.NoError(t, err1)
+	assert.NoError(t, err2)
+	assert.Equal(t, NewUint128(0x0102), x)
+	assert.Equal(t, NewUint256(0x0304), y)
+}
+
 func newBigInt(hex string) *big.Int {
 	i, _ := big.NewInt(0).SetString(hex, 16)
 	return i
@@ -0,0 +1,114 @@
+package bin
+
+import (
+	"encoding/binary"
+	"math/bits"
+)
+
+// xxHash64 (https://github.com/Cyan4973/xxHash) streaming implementation.
+const (
+	xxPrime1 uint64 = 11400714785074694791
+	xxPrime2 uint64 = 14029467366897019727
+	xxPrime3 uint64 = 1609587929392839161
+	xxPrime4 uint64 = 9650029242287828579
+	xxPrime5 uint64 = 2870177450012600261
+)
+
+type xxHash64 struct {
+	seed  uint64
+	v     [4]uint64
+	total uint64
+	mem   [32]byte
+	n     int
+}
+
+func (d *xxHash64) reset(seed uint64) {
+	d.seed = seed
+	d.v[0] = seed + xxPrime1 + xxPrime2
+	d.v[1] = seed + xxPrime2
+	d.v[2] = seed
+	d.v[3] = seed - xxPrime1
+	d.total = 0
+	d.n = 0
+}
+
+func (d *xxHash64) Write(p []byte) (int, error) {
+	n := len(p)
+	d.total += uint64(n)
+	if d.n+n < 32 {
+		d.n += copy(d.mem[d.n:], p)
+		return n, nil
+	}
+	if d.n > 0 {
+		c := copy(d.mem[d.n:], p)
+		p = p[c:]
+		d.blocks(d.mem[:])
+		d.n = 0
+	}
+	if len(p) >= 32 {
+		m := len(p) &^ 31
+		d.blocks(p[:m])
+		p = p[m:]
+	}
+	d.n = copy(d.mem[:], p)
+	return n, nil
+}
+
+func (d *xxHash64) blocks(p []byte) {
+	v1, v2, v3, v4 := d.v[0], d.v[1], d.v[2], d.v[3]
+	for ; len(p) >= 32; p = p[32:] {
+		v1 = xxRound(v1, binary.LittleEndian.Uint64(p[0:8]))
+		v2 = xxRound(v2, binary.LittleEndian.Uint64(p[8:16]))
+		v3 = xxRound(v3, binary.LittleEndian.Uint64(p[16:24]))
+		v4 = xxRound(v4, binary.LittleEndian.Uint64(p[24:32]))
+	}
+	d.v[0], d.v[1], d.v[2], d.v[3] = v1, v2, v3, v4
+}
+
+func (d *xxHash64) Sum64() uint64 {
+	var h uint64
+	if d.total >= 32 {
+		v1, v2, v3, v4 := d.v[0], d.v[1], d.v[2], d.v[3]
+		h = bits.RotateLeft64(v1, 1) + bits.RotateLeft64(v2, 7) + bits.RotateLeft64(v3, 12) + bits.RotateLeft64(v4, 18)
+		h = xxMergeRound(h, v1)
+		h = xxMergeRound(h, v2)
+		h = xxMergeRound(h, v3)
+		h = xxMergeRound(h, v4)
+	} else {
+		h = d.seed + xxPrime5
+	}
+	h += d.total
+
+	p := d.mem[:d.n]
+	for ; len(p) >= 8; p = p[8:] {
+		h ^= xxRound(0, binary.LittleEndian.Uint64(p))
+		h = bits.RotateLeft64(h, 27)*xxPrime1 + xxPrime4
+	}
+	if len(p) >= 4 {
+		h ^= uint64(binary.LittleEndian.Uint32(p)) * xxPrime1
+		h = bits.RotateLeft64(h, 23)*xxPrime2 + xxPrime3
+		p = p[4:]
+	}
+	for _, c := range p {
+		h ^= uint64(c) * xxPrime5
+		h = bits.RotateLeft64(h, 11) * xxPrime1
+	}
+
+	h ^= h >> 33
+	h *= xxPrime2
+	h ^= h >> 29
+	h *= xxPrime3
+	h ^= h >> 32
+	return h
+}
+
+func xxRound(acc, input uint64) uint64 {
+	acc += input * xxPrime2
+	acc = bits.RotateLeft64(acc, 31)
+	return acc * xxPrime1
+}
+
+func xxMergeRound(acc, val uint64) uint64 {
+	acc ^= xxRound(0, val)
+	return acc*xxPrime1 + xxPrime4
+}